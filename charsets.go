@@ -2,6 +2,7 @@
 package ecg
 
 import (
+	"bytes"
 	"fmt"
 	"golang.org/x/exp/maps"
 		"sort"
@@ -17,36 +18,141 @@ type CharSetSummary struct {
 	Utf8Bom    int `value:"utf-8-bom"`
 	Sets       map[string]int
 	OtherTotal int
+	// Files starting with a byte order mark of any kind
+	Boms int
+	// Files which are pure ASCII, they are counted as undetected in Sets as they fit most charsets
+	ASCII int
+	// Sum of the detector's confidence (0 to 100) for each of the Sets
+	Confidence map[string]int
 }
 
-// BestFit ...
+// CharsetUTF8BOM the charset ReadFile gives UTF-8 files starting with a byte order mark, the detector can't tell them
+// apart from UTF-8
+const CharsetUTF8BOM = "utf-8-bom"
+
+// byteOrderMarks byte order marks and the charset they mark, UTF-32LE has to be checked before UTF-16LE which it starts
+// with
+var byteOrderMarks = []struct {
+	bom     []byte
+	charset string
+}{
+	{[]byte{0xEF, 0xBB, 0xBF}, CharsetUTF8BOM},
+	{[]byte{0xFF, 0xFE, 0x00, 0x00}, "UTF-32LE"},
+	{[]byte{0x00, 0x00, 0xFE, 0xFF}, "UTF-32BE"},
+	{[]byte{0xFF, 0xFE}, "UTF-16LE"},
+	{[]byte{0xFE, 0xFF}, "UTF-16BE"},
+}
+
+// SniffBOM the charset of the byte order mark b starts with, named as the detector names them (except
+// CharsetUTF8BOM), or "" if there isn't one
+func SniffBOM(b []byte) string {
+	for _, m := range byteOrderMarks {
+		if bytes.HasPrefix(b, m.bom) {
+			return m.charset
+		}
+	}
+	return ""
+}
+
+// DetectorConfidence the mean confidence (0 to 1) the detector had in the charset
+func (s *CharSetSummary) DetectorConfidence(charset string) float64 {
+	if s.Sets[charset] == 0 {
+		return 0
+	}
+	return float64(s.Confidence[charset]) / float64(s.Sets[charset]) / 100
+}
+
+// EditorConfigCharset the editorconfig charset for a charset as the detector names it, false if editorconfig has no
+// value for it. Editorconfig charsets are returned as is.
+func EditorConfigCharset(detected string) (string, bool) {
+	switch strings.ToLower(detected) {
+	case "utf-8":
+		return "utf-8", true
+	case "iso-8859-1", "latin1":
+		return "latin1", true
+	case "utf-16le":
+		return "utf-16le", true
+	case "utf-16be":
+		return "utf-16be", true
+	case CharsetUTF8BOM:
+		return CharsetUTF8BOM, true
+	}
+	return detected, false
+}
+
+// asciiCompatible true if pure ASCII files are also valid in the charset
+func asciiCompatible(charset string) bool {
+	charset = strings.ToLower(charset)
+	return charset != CharsetUTF8BOM && !strings.HasPrefix(charset, "utf-16") && !strings.HasPrefix(charset, "utf-32")
+}
+
+// Compatible the number of files which are valid in the charset, those in it and pure ASCII ones if it can hold them
+func (s *CharSetSummary) Compatible(charset string) int {
+	n := s.Sets[charset]
+	if asciiCompatible(charset) {
+		n += s.ASCII
+	}
+	return n
+}
+
+// BestFit the most common charset which most of the files are compatible with, or "" if there isn't one
 func (s *CharSetSummary) BestFit() string {
 	ks := maps.Keys(s.Sets)
 	sort.Slice(ks, func(i, j int) bool {
 		a := ks[i]
 		b := ks[j]
-		return s.Sets[a] > s.Sets[b]
-		})
-	if len(ks) > 0 {
-		return ks[0]
+		if s.Sets[a] != s.Sets[b] {
+			return s.Sets[a] > s.Sets[b]
+		}
+		return a < b
+	})
+	total := 0
+	for _, n := range s.Sets {
+		total += n
+	}
+	for _, k := range ks {
+		if k == "" {
+			continue
+		}
+		if s.Compatible(k)*2 > total {
+			return k
+		}
+		break
 	}
 	return ""
 }
 
-// Distribution ...
+// Distribution the share of the total files in each charset, ie `utf-8 (60.0%), ascii (40.0%)`. Pure ASCII files are
+// given as ascii and those the detector couldn't tell as unknown
 func (s *CharSetSummary) Distribution(total int) string {
-	ks := maps.Keys(s.Sets)
+	counts := map[string]int{}
+	for k, n := range s.Sets {
+		if k != "" {
+			counts[k] += n
+			continue
+		}
+		if s.ASCII > 0 {
+			counts["ascii"] += s.ASCII
+		}
+		if n > s.ASCII {
+			counts["unknown"] += n - s.ASCII
+		}
+	}
+	ks := maps.Keys(counts)
 	sort.Slice(ks, func(i, j int) bool {
 		a := ks[i]
 		b := ks[j]
-		return s.Sets[a] > s.Sets[b]
+		if counts[a] != counts[b] {
+			return counts[a] > counts[b]
+		}
+		return a < b
 		})
 	r := &strings.Builder{}
 	for i, e := range ks {
 		if i > 0 {
 			r.WriteString(", ")
 		}
-		_, _ = fmt.Fprintf(r, "%s (%0.1f%%)", e, float64(s.Sets[e])/float64(total)*100)
+		_, _ = fmt.Fprintf(r, "%s (%0.1f%%)", e, float64(counts[e])/float64(total)*100)
 	}
 	return r.String()
 }
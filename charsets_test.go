@@ -0,0 +1,93 @@
+package ecg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEditorConfigCharset(t *testing.T) {
+	tests := []struct {
+		detected string
+		want     string
+		wantOk   bool
+	}{
+		{detected: "UTF-8", want: "utf-8", wantOk: true},
+		{detected: "ISO-8859-1", want: "latin1", wantOk: true},
+		{detected: "UTF-16LE", want: "utf-16le", wantOk: true},
+		{detected: "UTF-16BE", want: "utf-16be", wantOk: true},
+		{detected: CharsetUTF8BOM, want: CharsetUTF8BOM, wantOk: true},
+		{detected: "latin1", want: "latin1", wantOk: true},
+		{detected: "Shift_JIS", want: "Shift_JIS", wantOk: false},
+		{detected: "windows-1252", want: "windows-1252", wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.detected, func(t *testing.T) {
+			got, ok := EditorConfigCharset(tt.detected)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("EditorConfigCharset() = %s, %v; want %s, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestCharSetSummary_BestFit(t *testing.T) {
+	tests := []struct {
+		name  string
+		sets  map[string]int
+		ascii int
+		want  string
+	}{
+		{name: "All ASCII", sets: map[string]int{"": 10}, ascii: 10, want: ""},
+		{name: "ASCII counts towards utf-8", sets: map[string]int{"": 7, "utf-8": 3}, ascii: 7, want: "utf-8"},
+		{name: "ASCII doesn't count towards utf-8-bom", sets: map[string]int{"": 7, CharsetUTF8BOM: 3}, ascii: 7, want: ""},
+		{name: "Byte order marks dominate", sets: map[string]int{"": 2, CharsetUTF8BOM: 8}, ascii: 2, want: CharsetUTF8BOM},
+		{name: "Undetected aren't ASCII", sets: map[string]int{"": 7, "utf-8": 3}, ascii: 0, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &CharSetSummary{Sets: tt.sets, ASCII: tt.ascii}
+			if got := s.BestFit(); got != tt.want {
+				t.Errorf("BestFit() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCharSetSummary_Distribution(t *testing.T) {
+	tests := []struct {
+		name  string
+		sets  map[string]int
+		ascii int
+		want  string
+	}{
+		{name: "All ASCII", sets: map[string]int{"": 10}, ascii: 10, want: "ascii (100.0%)"},
+		{name: "Mostly ASCII", sets: map[string]int{"": 59, "utf-8": 1}, ascii: 59, want: "ascii (98.3%), utf-8 (1.7%)"},
+		{name: "Undetected aren't ASCII", sets: map[string]int{"": 3, "utf-8": 1}, ascii: 1, want: "unknown (50.0%), ascii (25.0%), utf-8 (25.0%)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &CharSetSummary{Sets: tt.sets, ASCII: tt.ascii}
+			total := 0
+			for _, n := range tt.sets {
+				total += n
+			}
+			if got := s.Distribution(total); got != tt.want {
+				t.Errorf("Distribution() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBasicSurveyor_SummarizeUnsupportedCharset(t *testing.T) {
+	l := NewBasicSurveyor()
+	l.Files = 3
+	l.CharacterSets.Sets["Shift_JIS"] = 3
+	l.CharacterSets.Confidence["Shift_JIS"] = 300
+	l.Summarize()
+	if l.Charset != "" {
+		t.Errorf("Charset = %q, want it left out", l.Charset)
+	}
+	if !strings.Contains(l.Charsets, "Shift_JIS isn't a charset editorconfig supports") {
+		t.Errorf("Charsets = %q, want it to explain Shift_JIS isn't supported", l.Charsets)
+	}
+}
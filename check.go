@@ -0,0 +1,172 @@
+package ecg
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Violation a property a file doesn't conform to
+type Violation struct {
+	Filename string
+	Property string
+	// The value the editorconfig file declares
+	Expected string
+	// What was found instead
+	Found string
+}
+
+// String ...
+func (v *Violation) String() string {
+	return fmt.Sprintf("%s: %s should be %s but %s", v.Filename, v.Property, v.Expected, v.Found)
+}
+
+// Check reads every file in dir which isn't ignored and reports where it doesn't conform to the editorconfig files,
+// which can be loaded with LoadEditorConfigs. Checks indent_style, end_of_line, trim_trailing_whitespace,
+// insert_final_newline, charset and max_line_length.
+// Files which can't be read are left out and the check carries on, they are returned joined together as FileErrors
+// along with the violations of the rest. WithStrict stops at the first instead.
+func Check(dir fs.FS, configs EditorConfigs, ignore func(file *File) bool, opts ...Option) ([]*Violation, error) {
+	var violations []*Violation
+	var errs []error
+	o := newAnalyzeOptions(opts)
+	fail := func(fe *FileError) error {
+		if o.strict {
+			return fe
+		}
+		errs = append(errs, fe)
+		return nil
+	}
+	err := fs.WalkDir(dir, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil && path == "." {
+			return err
+		}
+		if err != nil {
+			return fail(&FileError{Filename: path, Err: err})
+		}
+		if skip, err := o.skip(path, d); skip {
+			return err
+		}
+		if d.IsDir() || d.Name() == EditorConfigFilename {
+			return nil
+		}
+		f := &File{
+			Filename:   path,
+			FileOpener: dir,
+		}
+		if ignore(f) {
+			return nil
+		}
+		properties := configs.PropertiesFor(path)
+		if len(properties) == 0 {
+			return nil
+		}
+		vs, err := CheckFile(f, properties)
+		if err != nil {
+			return fail(&FileError{Filename: path, Err: err})
+		}
+		violations = append(violations, vs...)
+		return nil
+	})
+	if err != nil {
+		return violations, fmt.Errorf("checking: %w", err)
+	}
+	return violations, errors.Join(errs...)
+}
+
+// CheckFile reports where a single file doesn't conform to the properties
+func CheckFile(f *File, properties map[string]string) ([]*Violation, error) {
+	charset, finalNewLine, survey, err := NewBasicSurveyor().ReadFile(f)
+	if err != nil {
+		return nil, err
+	}
+	var violations []*Violation
+	add := func(property, found string) {
+		violations = append(violations, &Violation{
+			Filename: f.Filename,
+			Property: property,
+			Expected: properties[property],
+			Found:    found,
+		})
+	}
+	switch normalizeValue(properties["indent_style"]) {
+	case "tab":
+		if n := survey.IndentedWith(' '); n > 0 {
+			add("indent_style", fmt.Sprintf("%d lines are indented with spaces", n))
+		}
+	case "space":
+		if n := survey.IndentedWith('\t'); n > 0 {
+			add("indent_style", fmt.Sprintf("%d lines are indented with tabs", n))
+		}
+	}
+	switch strings.ToLower(properties["end_of_line"]) {
+	case "lf":
+		if survey.WindowNewlines > 0 {
+			add("end_of_line", fmt.Sprintf("%d lines end in crlf", survey.WindowNewlines))
+		}
+	case "crlf":
+		if n := survey.NewLines - survey.WindowNewlines; n > 0 {
+			add("end_of_line", fmt.Sprintf("%d lines end in lf", n))
+		}
+	}
+	if strings.EqualFold(properties["trim_trailing_whitespace"], "true") {
+		if n := survey.TrailingWhitespaceLines(); n > 0 {
+			add("trim_trailing_whitespace", fmt.Sprintf("%d lines have trailing whitespace", n))
+		}
+	}
+	if f.Size() != 0 {
+		switch strings.ToLower(properties["insert_final_newline"]) {
+		case "true":
+			if !finalNewLine {
+				add("insert_final_newline", "there is no final new line")
+			}
+		case "false":
+			if finalNewLine {
+				add("insert_final_newline", "there is a final new line")
+			}
+		}
+	}
+	if expected := properties["charset"]; expected != "" && !CharsetCompatible(expected, charset, survey) {
+		add("charset", fmt.Sprintf("it looks like %s", charset))
+	}
+	if maxLineLength, err := strconv.Atoi(properties["max_line_length"]); err == nil && maxLineLength > 0 {
+		tabWidth, err := strconv.Atoi(properties["tab_width"])
+		if err != nil {
+			tabWidth, err = strconv.Atoi(properties["indent_size"])
+		}
+		if err != nil || tabWidth <= 0 {
+			tabWidth = 8
+		}
+		if n := survey.LinesLongerThan(maxLineLength, tabWidth); n > 0 {
+			add("max_line_length", fmt.Sprintf("%d lines are longer", n))
+		}
+	}
+	sort.Slice(violations, func(i, j int) bool {
+		return violations[i].Property < violations[j].Property
+	})
+	return violations, nil
+}
+
+// CharsetCompatible true if a detected charset is compatible with the editorconfig charset, undetected charsets and
+// pure ASCII files are compatible with anything but utf-8-bom
+func CharsetCompatible(expected, detected string, survey *LineSurvey) bool {
+	expected = strings.ToLower(expected)
+	if expected == CharsetUTF8BOM {
+		// Without the byte order mark it isn't utf-8-bom however plain the file is
+		return detected == CharsetUTF8BOM
+	}
+	if detected == CharsetUTF8BOM {
+		return false
+	}
+	if detected == "" || (survey != nil && survey.NonASCII == 0) {
+		return true
+	}
+	if _, ok := EditorConfigCharset(expected); !ok {
+		return true
+	}
+	charset, _ := EditorConfigCharset(detected)
+	return charset == expected
+}
@@ -0,0 +1,39 @@
+package ecg
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCheck(t *testing.T) {
+	mapFS := fstest.MapFS{
+		".editorconfig": &fstest.MapFile{Data: []byte("root = true\n[*]\nend_of_line = lf\ninsert_final_newline = true\ntrim_trailing_whitespace = true\n[*.go]\nindent_style = tab\nmax_line_length = 20\n")},
+		"good.go":       &fstest.MapFile{Data: []byte("package main\n\nfunc main() {\n\tprintln()\n}\n")},
+		"bad.go":        &fstest.MapFile{Data: []byte("package main \r\n\r\nfunc main() {\r\n    println(\"a long line\")\r\n}")},
+		"notes.txt":     &fstest.MapFile{Data: []byte("    spaces are fine here\n")},
+	}
+	configs, err := LoadEditorConfigs(mapFS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	violations, err := Check(mapFS, configs, func(file *File) bool { return false })
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, v := range violations {
+		got = append(got, v.String())
+	}
+	want := []string{
+		"bad.go: end_of_line should be lf but 4 lines end in crlf",
+		"bad.go: indent_style should be tab but 1 lines are indented with spaces",
+		"bad.go: insert_final_newline should be true but there is no final new line",
+		"bad.go: max_line_length should be 20 but 1 lines are longer",
+		"bad.go: trim_trailing_whitespace should be true but 1 lines have trailing whitespace",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Check() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,173 @@
+package ecg
+
+import (
+	"bytes"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// filetypeAliases interpreters and editor mode names which are another name for a filetype, anything else is used as
+// it is once version numbers are dropped
+var filetypeAliases = map[string]string{
+	"ash":          "sh",
+	"bash":         "sh",
+	"dash":         "sh",
+	"ksh":          "sh",
+	"mksh":         "sh",
+	"shell-script": "sh",
+	"tcsh":         "csh",
+	"py":           "python",
+	"rb":           "ruby",
+	"node":         "javascript",
+	"nodejs":       "javascript",
+	"js":           "javascript",
+	"gmake":        "make",
+	"makefile":     "make",
+}
+
+var (
+	// Vim modelines, ie `# vim: set ft=python:` or `// vi: filetype=sh`
+	vimModeline = regexp.MustCompile(`(?:^|\s)(?:vi|vim|ex):.*?\b(?:ft|filetype|syntax)=([\w+-]+)`)
+	// Emacs modelines, ie `# -*- mode: python; coding: utf-8 -*-` or `-*- ruby -*-`
+	emacsModeline = regexp.MustCompile(`-\*-\s*(?:.*?\bmode:\s*([\w+-]+).*?|([\w+-]+)\s*)-\*-`)
+	// Trailing version numbers of interpreters, ie python3.11 or perl5
+	interpreterVersion = regexp.MustCompile(`[\d.]+$`)
+)
+
+// modelineLines how many lines at the start and end of a file editors look for modelines in
+const modelineLines = 5
+
+// classify the filetype of a file without an extension from the start of its content, see File.Filetype
+func classify(filename string, head []byte) string {
+	if path.Ext(path.Base(filename)) != "" {
+		return ""
+	}
+	if ft := shebangFiletype(head); ft != "" {
+		return ft
+	}
+	if ft := modelineFiletype(head); ft != "" {
+		return ft
+	}
+	return sniffFiletype(head)
+}
+
+// normalizeFiletype lower cases the name, drops version numbers and resolves aliases
+func normalizeFiletype(name string) string {
+	name = strings.ToLower(name)
+	if trimmed := interpreterVersion.ReplaceAllString(name, ""); trimmed != "" {
+		name = trimmed
+	}
+	if alias, ok := filetypeAliases[name]; ok {
+		return alias
+	}
+	return name
+}
+
+// shebangFiletype the interpreter named by a `#!` line, looking through env and its flags, ie
+// `#!/usr/bin/env -S python3 -u` is python
+func shebangFiletype(head []byte) string {
+	if !bytes.HasPrefix(head, []byte("#!")) {
+		return ""
+	}
+	line, _, _ := bytes.Cut(head[2:], []byte("\n"))
+	fields := strings.Fields(string(line))
+	if len(fields) == 0 {
+		return ""
+	}
+	interpreter := path.Base(fields[0])
+	if interpreter == "env" {
+		interpreter = ""
+		for _, f := range fields[1:] {
+			if strings.HasPrefix(f, "-") || strings.Contains(f, "=") {
+				continue
+			}
+			interpreter = path.Base(f)
+			break
+		}
+	}
+	if interpreter == "" {
+		return ""
+	}
+	return normalizeFiletype(interpreter)
+}
+
+// modelineFiletype the filetype set by a vim modeline in the first or last few lines, or an emacs one in the first
+// two
+func modelineFiletype(head []byte) string {
+	lines := strings.Split(strings.TrimRight(string(head), "\n"), "\n")
+	candidates := lines
+	if len(lines) > modelineLines*2 {
+		candidates = append(append([]string{}, lines[:modelineLines]...), lines[len(lines)-modelineLines:]...)
+	}
+	for _, l := range candidates {
+		if m := vimModeline.FindStringSubmatch(l); m != nil {
+			return normalizeFiletype(m[1])
+		}
+	}
+	for _, l := range lines[:min(len(lines), 2)] {
+		if m := emacsModeline.FindStringSubmatch(l); m != nil {
+			return normalizeFiletype(m[1] + m[2])
+		}
+	}
+	return ""
+}
+
+// sniffFiletype recognises the few languages whose files say what they are in the first few bytes
+func sniffFiletype(head []byte) string {
+	start := strings.ToLower(string(bytes.TrimSpace(head[:min(len(head), 256)])))
+	switch {
+	case strings.HasPrefix(start, "<?php"):
+		return "php"
+	case strings.HasPrefix(start, "<?xml"):
+		return "xml"
+	case strings.HasPrefix(start, "<!doctype html"), strings.HasPrefix(start, "<html"):
+		return "html"
+	case strings.HasPrefix(start, "pipeline {"), strings.HasPrefix(start, "@library("):
+		// Jenkins pipelines
+		return "groovy"
+	}
+	return ""
+}
+
+// Filetype the language a file without an extension is written in going by its shebang line, editor modeline or
+// content, named as vim names it, ie "sh", "python" or "ruby". "" for files with an extension or which can't be told.
+func (fd *File) Filetype() string {
+	survey, err := fd.Survey()
+	if err != nil {
+		return ""
+	}
+	return survey.Filetype
+}
+
+// HasFiletype true if the file was classified as one of the filetypes
+func (fd *File) HasFiletype(filetypes []string) bool {
+	ft := fd.Filetype()
+	if ft == "" {
+		return false
+	}
+	for _, f := range filetypes {
+		if f == ft {
+			return true
+		}
+	}
+	return false
+}
+
+// ExactGlob an editorconfig glob which only matches the slash separated path, relative to the directory the
+// .editorconfig is in
+func ExactGlob(filename string) string {
+	return "/" + EscapeGlob(strings.TrimPrefix(filename, "/"))
+}
+
+// EscapeGlob escapes the characters editorconfig globs treat specially so s only matches itself
+func EscapeGlob(s string) string {
+	sb := &strings.Builder{}
+	for _, r := range s {
+		if strings.ContainsRune(`*?[]{},!\`, r) {
+			sb.WriteRune('\\')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
@@ -0,0 +1,92 @@
+package ecg_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	ecg "editorconfig-guesser"
+	_ "editorconfig-guesser/fileformats"
+)
+
+func TestFile_Filetype(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		content  string
+		want     string
+	}{
+		{"bash", "deploy", "#!/bin/bash\necho hi\n", "sh"},
+		{"env", "run", "#!/usr/bin/env python3\nprint()\n", "python"},
+		{"env flags", "run", "#!/usr/bin/env -S LANG=C python3.11 -u\nprint()\n", "python"},
+		{"node", "cli", "#!/usr/bin/env node\nmain()\n", "javascript"},
+		{"extension", "deploy.txt", "#!/bin/bash\necho hi\n", ""},
+		{"vim", "tool", "x = 1\n# vim: set ft=ruby:\n", "ruby"},
+		{"emacs", "tool", "# -*- mode: python; coding: utf-8 -*-\nx = 1\n", "python"},
+		{"emacs short", "tool", "# -*- perl -*-\n1;\n", "perl"},
+		{"emacs coding", "tool", "# -*- coding: utf-8 -*-\nx = 1\n", ""},
+		{"php", "index", "<?php\necho 1;\n", "php"},
+		{"jenkins", "Jenkinsfile-deploy", "pipeline {\n  agent any\n}\n", "groovy"},
+		{"plain", "README", "Read me\n", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &ecg.File{
+				Filename:   tt.filename,
+				FileOpener: fstest.MapFS{tt.filename: &fstest.MapFile{Data: []byte(tt.content)}},
+			}
+			if got := f.Filetype(); got != tt.want {
+				t.Errorf("Filetype() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExactGlob(t *testing.T) {
+	for filename, want := range map[string]string{
+		"bin/deploy":     "/bin/deploy",
+		"odd[1]{a,b}":    `/odd\[1\]\{a\,b\}`,
+		"/scripts/run-1": "/scripts/run-1",
+	} {
+		glob := ecg.ExactGlob(filename)
+		if glob != want {
+			t.Errorf("ExactGlob(%q) = %q, want %q", filename, glob, want)
+		}
+		if !ecg.MatchGlob(glob, filename) {
+			t.Errorf("MatchGlob(%q, %q) = false", glob, filename)
+		}
+	}
+	if ecg.MatchGlob(ecg.ExactGlob("deploy"), "bin/deploy") {
+		t.Error("ExactGlob(deploy) matches bin/deploy")
+	}
+}
+
+func TestAnalyzeFiletypes(t *testing.T) {
+	dir := fstest.MapFS{
+		"a.txt":         &fstest.MapFile{Data: []byte("one\n  two\n  three\n")},
+		"b.txt":         &fstest.MapFile{Data: []byte("one\n  two\n  three\n")},
+		"bin/deploy":    &fstest.MapFile{Data: []byte("#!/bin/bash\n\tone\n\ttwo\n\tthree\n\tfour\n\tfive\n\tsix\n\tseven\n\teight\n")},
+		"scripts/tool":  &fstest.MapFile{Data: []byte("#!/usr/bin/env python3\nprint()\n")},
+		"scripts/notes": &fstest.MapFile{Data: []byte("just text\n")},
+	}
+	guess, err := ecg.Analyze(dir, func(f *ecg.File) bool { return false })
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	shell := guess.Section("/bin/deploy")
+	if shell == nil {
+		t.Fatalf("no [/bin/deploy] section in:\n%s", guess)
+	}
+	if p := shell.Get("indent_style"); p == nil || p.Value != "tabs" {
+		t.Errorf("[/bin/deploy] indent_style = %v, want tabs", p)
+	}
+	if guess.Section("/scripts/tool") == nil {
+		t.Errorf("no [/scripts/tool] section in:\n%s", guess)
+	}
+	for _, s := range guess.Sections {
+		for _, g := range s.FileGlobs {
+			if g == "/scripts/notes" {
+				t.Errorf("%s claimed scripts/notes", s.Format)
+			}
+		}
+	}
+}
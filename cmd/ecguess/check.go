@@ -0,0 +1,166 @@
+// Generated by github.com/arran4/go-subcommand/cmd/gosubc
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"editorconfig-guesser/internal/cli"
+)
+
+var _ Cmd = (*Check)(nil)
+
+type Check struct {
+	*RootCmd
+	Flags         *flag.FlagSet
+	verboseFlag   bool
+	configFile    string
+	args          []string
+	SubCommands   map[string]Cmd
+	CommandAction func(c *Check) error
+}
+
+type UsageDataCheck struct {
+	*Check
+	Recursive bool
+}
+
+func (c *Check) Usage() {
+	err := executeUsage(os.Stderr, "check_usage.txt", UsageDataCheck{c, false})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating usage: %s\n", err)
+	}
+}
+
+func (c *Check) UsageRecursive() {
+	err := executeUsage(os.Stderr, "check_usage.txt", UsageDataCheck{c, true})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating usage: %s\n", err)
+	}
+}
+
+func (c *Check) Execute(args []string) error {
+	if len(args) > 0 {
+		if cmd, ok := c.SubCommands[args[0]]; ok {
+			return cmd.Execute(args[1:])
+		}
+	}
+	var remainingArgs []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			remainingArgs = append(remainingArgs, args[i+1:]...)
+			break
+		}
+		if strings.HasPrefix(arg, "-") && arg != "-" {
+			name := arg
+			value := ""
+			hasValue := false
+			if strings.Contains(arg, "=") {
+				parts := strings.SplitN(arg, "=", 2)
+				name = parts[0]
+				value = parts[1]
+				hasValue = true
+			}
+			trimmedName := strings.TrimLeft(name, "-")
+			switch trimmedName {
+
+			case "verboseFlag", "verbose", "v":
+				if hasValue {
+					b, err := strconv.ParseBool(value)
+					if err != nil {
+						return fmt.Errorf("invalid boolean value for flag %s: %s", name, value)
+					}
+					c.verboseFlag = b
+				} else {
+					c.verboseFlag = true
+				}
+			case "configFile", "config":
+				if !hasValue {
+					if i+1 >= len(args) {
+						return fmt.Errorf("flag %s requires a value", name)
+					}
+					value = args[i+1]
+					i++
+				}
+				c.configFile = value
+			case "help", "h":
+				c.Usage()
+				return nil
+			default:
+				return fmt.Errorf("unknown flag: %s", name)
+			}
+		} else {
+			remainingArgs = append(remainingArgs, arg)
+		}
+	}
+	// Handle vararg args
+	{
+		varArgStart := 0
+		if varArgStart > len(remainingArgs) {
+			varArgStart = len(remainingArgs)
+		}
+		varArgs := remainingArgs[varArgStart:]
+		c.args = varArgs
+	}
+
+	if c.CommandAction != nil {
+		if err := c.CommandAction(c); err != nil {
+			return fmt.Errorf("check failed: %w", err)
+		}
+	} else {
+		c.Usage()
+	}
+
+	return nil
+}
+
+func (c *RootCmd) NewCheck() *Check {
+	set := flag.NewFlagSet("check", flag.ContinueOnError)
+	v := &Check{
+		RootCmd:     c,
+		Flags:       set,
+		SubCommands: make(map[string]Cmd),
+	}
+
+	set.BoolVar(&v.verboseFlag, "verbose", false, "Logs more than what is required")
+	set.BoolVar(&v.verboseFlag, "v", false, "Logs more than what is required")
+	set.StringVar(&v.configFile, "config", "", "Config file read after the user and project ones")
+	set.Usage = v.Usage
+
+	v.CommandAction = func(c *Check) error {
+		return cli.Check(c.verboseFlag, c.configFile, c.args...)
+	}
+
+	v.SubCommands["help"] = &InternalCommand{
+		Exec: func(args []string) error {
+			for _, arg := range args {
+				if arg == "-deep" {
+					v.UsageRecursive()
+					return nil
+				}
+			}
+			v.Usage()
+			return nil
+		},
+		UsageFunc: v.Usage,
+	}
+	v.SubCommands["usage"] = &InternalCommand{
+		Exec: func(args []string) error {
+			for _, arg := range args {
+				if arg == "-deep" {
+					v.UsageRecursive()
+					return nil
+				}
+			}
+			v.Usage()
+			return nil
+		},
+		UsageFunc: v.Usage,
+	}
+	return v
+}
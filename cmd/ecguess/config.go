@@ -0,0 +1,200 @@
+// Generated by github.com/arran4/go-subcommand/cmd/gosubc
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"editorconfig-guesser/internal/cli"
+)
+
+var _ Cmd = (*Config)(nil)
+
+type Config struct {
+	*RootCmd
+	Flags          *flag.FlagSet
+	configFile     string
+	thresholds     string
+	formats        string
+	disableFormats string
+	ignorePatterns string
+	args           []string
+	SubCommands    map[string]Cmd
+	CommandAction  func(c *Config) error
+}
+
+type UsageDataConfig struct {
+	*Config
+	Recursive bool
+}
+
+func (c *Config) Usage() {
+	err := executeUsage(os.Stderr, "config_usage.txt", UsageDataConfig{c, false})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating usage: %s\n", err)
+	}
+}
+
+func (c *Config) UsageRecursive() {
+	err := executeUsage(os.Stderr, "config_usage.txt", UsageDataConfig{c, true})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating usage: %s\n", err)
+	}
+}
+
+func (c *Config) Execute(args []string) error {
+	if len(args) > 0 {
+		if cmd, ok := c.SubCommands[args[0]]; ok {
+			return cmd.Execute(args[1:])
+		}
+	}
+	var remainingArgs []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			remainingArgs = append(remainingArgs, args[i+1:]...)
+			break
+		}
+		if strings.HasPrefix(arg, "-") && arg != "-" {
+			name := arg
+			value := ""
+			hasValue := false
+			if strings.Contains(arg, "=") {
+				parts := strings.SplitN(arg, "=", 2)
+				name = parts[0]
+				value = parts[1]
+				hasValue = true
+			}
+			trimmedName := strings.TrimLeft(name, "-")
+			switch trimmedName {
+
+			case "configFile", "config":
+				if !hasValue {
+					if i+1 >= len(args) {
+						return fmt.Errorf("flag %s requires a value", name)
+					}
+					value = args[i+1]
+					i++
+				}
+				c.configFile = value
+			case "thresholds", "threshold":
+				if !hasValue {
+					if i+1 >= len(args) {
+						return fmt.Errorf("flag %s requires a value", name)
+					}
+					value = args[i+1]
+					i++
+				}
+				c.thresholds = value
+			case "formats":
+				if !hasValue {
+					if i+1 >= len(args) {
+						return fmt.Errorf("flag %s requires a value", name)
+					}
+					value = args[i+1]
+					i++
+				}
+				c.formats = value
+			case "disableFormats", "disable-formats":
+				if !hasValue {
+					if i+1 >= len(args) {
+						return fmt.Errorf("flag %s requires a value", name)
+					}
+					value = args[i+1]
+					i++
+				}
+				c.disableFormats = value
+			case "ignorePatterns", "ignore":
+				if !hasValue {
+					if i+1 >= len(args) {
+						return fmt.Errorf("flag %s requires a value", name)
+					}
+					value = args[i+1]
+					i++
+				}
+				c.ignorePatterns = value
+			case "help", "h":
+				c.Usage()
+				return nil
+			default:
+				return fmt.Errorf("unknown flag: %s", name)
+			}
+		} else {
+			remainingArgs = append(remainingArgs, arg)
+		}
+	}
+	// Handle vararg args
+	{
+		varArgStart := 0
+		if varArgStart > len(remainingArgs) {
+			varArgStart = len(remainingArgs)
+		}
+		varArgs := remainingArgs[varArgStart:]
+		c.args = varArgs
+	}
+
+	if c.CommandAction != nil {
+		if err := c.CommandAction(c); err != nil {
+			return fmt.Errorf("config failed: %w", err)
+		}
+	} else {
+		c.Usage()
+	}
+
+	return nil
+}
+
+func (c *RootCmd) NewConfig() *Config {
+	set := flag.NewFlagSet("config", flag.ContinueOnError)
+	v := &Config{
+		RootCmd:     c,
+		Flags:       set,
+		SubCommands: make(map[string]Cmd),
+	}
+
+	set.StringVar(&v.configFile, "config", "", "Config file read after the user and project ones")
+
+	set.StringVar(&v.thresholds, "threshold", "", "Comma separated name=value thresholds, ie final_newline=0.9")
+
+	set.StringVar(&v.formats, "formats", "", "Comma separated file formats to run, every format if empty")
+
+	set.StringVar(&v.disableFormats, "disable-formats", "", "Comma separated file formats not to run")
+
+	set.StringVar(&v.ignorePatterns, "ignore", "", "Comma separated gitignore style patterns of files to leave out")
+	set.Usage = v.Usage
+
+	v.CommandAction = func(c *Config) error {
+		return cli.ShowConfig(c.configFile, c.thresholds, c.formats, c.disableFormats, c.ignorePatterns, c.args...)
+	}
+
+	v.SubCommands["help"] = &InternalCommand{
+		Exec: func(args []string) error {
+			for _, arg := range args {
+				if arg == "-deep" {
+					v.UsageRecursive()
+					return nil
+				}
+			}
+			v.Usage()
+			return nil
+		},
+		UsageFunc: v.Usage,
+	}
+	v.SubCommands["usage"] = &InternalCommand{
+		Exec: func(args []string) error {
+			for _, arg := range args {
+				if arg == "-deep" {
+					v.UsageRecursive()
+					return nil
+				}
+			}
+			v.Usage()
+			return nil
+		},
+		UsageFunc: v.Usage,
+	}
+	return v
+}
@@ -0,0 +1,33 @@
+// Generated by github.com/arran4/go-subcommand/cmd/gosubc
+
+package main
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestConfig_Execute(t *testing.T) {
+
+	parent := &RootCmd{
+		FlagSet:  flag.NewFlagSet("root", flag.ContinueOnError),
+		Commands: make(map[string]Cmd),
+	}
+	cmd := parent.NewConfig()
+
+	called := false
+	cmd.CommandAction = func(c *Config) error {
+		called = true
+		return nil
+	}
+
+	args := []string{}
+
+	err := cmd.Execute(args)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if !called {
+		t.Error("CommandAction was not called")
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"errors"
+
+	"editorconfig-guesser/internal/cli"
+)
+
+// flagError a mistake on the command line a command found while parsing its flags, before it ran
+type flagError struct {
+	err error
+}
+
+func (e *flagError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap ...
+func (e *flagError) Unwrap() error {
+	return e.err
+}
+
+// actionError an error from running a command rather than parsing its flags, see ran
+type actionError struct {
+	err error
+}
+
+func (e *actionError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap ...
+func (e *actionError) Unwrap() error {
+	return e.err
+}
+
+// ran marks the errors of a command's action as actionErrors
+func ran[T any](action func(T) error) func(T) error {
+	return func(c T) error {
+		if err := action(c); err != nil {
+			return &actionError{err: err}
+		}
+		return nil
+	}
+}
+
+// flagErrors a command whose errors are flagErrors unless its action returned them
+type flagErrors struct {
+	Cmd
+}
+
+// Execute ...
+func (c *flagErrors) Execute(args []string) error {
+	err := c.Cmd.Execute(args)
+	var ae *actionError
+	if err != nil && !errors.As(err, &ae) {
+		return &flagError{err: err}
+	}
+	return err
+}
+
+// newRoot the generated root command with the errors of its commands' flags told apart from those of their actions,
+// the commands are generated so the errors are sorted out around them
+func newRoot(name, version, commit, date string) (*RootCmd, error) {
+	root, err := NewRoot(name, version, commit, date)
+	if err != nil {
+		return nil, err
+	}
+	for name, cmd := range root.Commands {
+		switch c := cmd.(type) {
+		case *Check:
+			c.CommandAction = ran(c.CommandAction)
+		case *Config:
+			c.CommandAction = ran(c.CommandAction)
+		case *Diff:
+			c.CommandAction = ran(c.CommandAction)
+		case *Fix:
+			c.CommandAction = ran(c.CommandAction)
+		case *Generate:
+			c.CommandAction = ran(c.CommandAction)
+		case *Outliers:
+			c.CommandAction = ran(c.CommandAction)
+		default:
+			continue
+		}
+		root.Commands[name] = &flagErrors{Cmd: cmd}
+	}
+	return root, nil
+}
+
+// exitCode the code ecguess exits with for err, mistakes on the command line are usage errors, see cli.ExitCode for
+// the rest
+func exitCode(err error) int {
+	var ue *UserError
+	var fe *flagError
+	if errors.As(err, &ue) || errors.As(err, &fe) {
+		return cli.ExitUsage
+	}
+	return cli.ExitCode(err)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"editorconfig-guesser/internal/cli"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{name: "no directories", args: []string{"generate"}, want: cli.ExitUsage},
+		{name: "unknown flag", args: []string{"generate", "--bogus", "."}, want: cli.ExitUsage},
+		{name: "flag without a value", args: []string{"generate", "--format"}, want: cli.ExitUsage},
+		{name: "invalid flag value", args: []string{"diff", "--min-confidence=high", "."}, want: cli.ExitUsage},
+		{name: "unknown format", args: []string{"generate", "--format", "toml", "."}, want: cli.ExitUsage},
+		{name: "missing directory", args: []string{"generate", "does-not-exist"}, want: cli.ExitAnalysis},
+		{name: "empty directory", args: []string{"generate", t.TempDir()}, want: cli.ExitNothingToGuess},
+		{name: "check without an editorconfig", args: []string{"check", t.TempDir()}, want: cli.ExitNoEditorConfig},
+		{name: "diff without an editorconfig", args: []string{"diff", t.TempDir()}, want: cli.ExitNoEditorConfig},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := newRoot("ecguess", "", "", "")
+			if err != nil {
+				t.Fatalf("Failed to create root command: %v", err)
+			}
+			if got := exitCode(root.Execute(tt.args)); got != tt.want {
+				t.Errorf("exitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExitCode_EmptyDirectoryAmongOthers(t *testing.T) {
+	empty, full := t.TempDir(), t.TempDir()
+	if err := os.WriteFile(filepath.Join(full, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	root, err := newRoot("ecguess", "", "", "")
+	if err != nil {
+		t.Fatalf("Failed to create root command: %v", err)
+	}
+	if got := exitCode(root.Execute([]string{"generate", "--save", empty, full})); got != cli.ExitNothingToGuess {
+		t.Errorf("exitCode() = %d, want %d", got, cli.ExitNothingToGuess)
+	}
+	if _, err := os.Stat(filepath.Join(full, ".editorconfig")); err != nil {
+		t.Errorf("the directory after the empty one wasn't guessed: %v", err)
+	}
+}
+
+func TestExitCode_ActionErrors(t *testing.T) {
+	root, err := newRoot("ecguess", "", "", "")
+	if err != nil {
+		t.Fatalf("Failed to create root command: %v", err)
+	}
+	generate, ok := root.Commands["generate"].(*flagErrors)
+	if !ok {
+		t.Fatalf("generate is a %T, want its flag errors told apart", root.Commands["generate"])
+	}
+	// Only what the flags get wrong is a usage error, whatever the action's error says
+	generate.Cmd.(*Generate).CommandAction = ran(func(c *Generate) error {
+		return errors.New("unknown flag: --bogus")
+	})
+	if got := exitCode(root.Execute([]string{"generate", "."})); got != cli.ExitFailure {
+		t.Errorf("exitCode() = %d, want %d", got, cli.ExitFailure)
+	}
+}
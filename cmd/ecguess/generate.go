@@ -16,12 +16,27 @@ var _ Cmd = (*Generate)(nil)
 
 type Generate struct {
 	*RootCmd
-	Flags         *flag.FlagSet
-	saveFlag      bool
-	verboseFlag   bool
-	args          []string
-	SubCommands   map[string]Cmd
-	CommandAction func(c *Generate) error
+	Flags          *flag.FlagSet
+	saveFlag       bool
+	mergeFlag      bool
+	verboseFlag    bool
+	confidenceFlag bool
+	minConfidence  float64
+	subdirectories int
+	nestedFlag     bool
+	format         string
+	sampling       string
+	jobs           int
+	readTimeout    string
+	strictFlag     bool
+	configFile     string
+	thresholds     string
+	formats        string
+	disableFormats string
+	ignorePatterns string
+	args           []string
+	SubCommands    map[string]Cmd
+	CommandAction  func(c *Generate) error
 }
 
 type UsageDataGenerate struct {
@@ -80,6 +95,17 @@ func (c *Generate) Execute(args []string) error {
 					c.saveFlag = true
 				}
 
+			case "mergeFlag", "merge":
+				if hasValue {
+					b, err := strconv.ParseBool(value)
+					if err != nil {
+						return fmt.Errorf("invalid boolean value for flag %s: %s", name, value)
+					}
+					c.mergeFlag = b
+				} else {
+					c.mergeFlag = true
+				}
+
 			case "verboseFlag", "verbose", "v":
 				if hasValue {
 					b, err := strconv.ParseBool(value)
@@ -90,6 +116,152 @@ func (c *Generate) Execute(args []string) error {
 				} else {
 					c.verboseFlag = true
 				}
+
+			case "confidenceFlag", "confidence":
+				if hasValue {
+					b, err := strconv.ParseBool(value)
+					if err != nil {
+						return fmt.Errorf("invalid boolean value for flag %s: %s", name, value)
+					}
+					c.confidenceFlag = b
+				} else {
+					c.confidenceFlag = true
+				}
+
+			case "minConfidence", "min-confidence":
+				if !hasValue {
+					if i+1 >= len(args) {
+						return fmt.Errorf("flag %s requires a value", name)
+					}
+					value = args[i+1]
+					i++
+				}
+				f, err := strconv.ParseFloat(value, 64)
+				if err != nil {
+					return fmt.Errorf("invalid float64 value for flag %s: %s", name, value)
+				}
+				c.minConfidence = f
+
+			case "subdirectories":
+				if !hasValue {
+					if i+1 >= len(args) {
+						return fmt.Errorf("flag %s requires a value", name)
+					}
+					value = args[i+1]
+					i++
+				}
+				n, err := strconv.Atoi(value)
+				if err != nil {
+					return fmt.Errorf("invalid int value for flag %s: %s", name, value)
+				}
+				c.subdirectories = n
+
+			case "nestedFlag", "nested":
+				if hasValue {
+					b, err := strconv.ParseBool(value)
+					if err != nil {
+						return fmt.Errorf("invalid boolean value for flag %s: %s", name, value)
+					}
+					c.nestedFlag = b
+				} else {
+					c.nestedFlag = true
+				}
+			case "format":
+				if !hasValue {
+					if i+1 >= len(args) {
+						return fmt.Errorf("flag %s requires a value", name)
+					}
+					value = args[i+1]
+					i++
+				}
+				c.format = value
+			case "sampling":
+				if !hasValue {
+					if i+1 >= len(args) {
+						return fmt.Errorf("flag %s requires a value", name)
+					}
+					value = args[i+1]
+					i++
+				}
+				c.sampling = value
+			case "jobs", "j":
+				if !hasValue {
+					if i+1 >= len(args) {
+						return fmt.Errorf("flag %s requires a value", name)
+					}
+					value = args[i+1]
+					i++
+				}
+				n, err := strconv.Atoi(value)
+				if err != nil {
+					return fmt.Errorf("invalid int value for flag %s: %s", name, value)
+				}
+				c.jobs = n
+			case "readTimeout", "read-timeout":
+				if !hasValue {
+					if i+1 >= len(args) {
+						return fmt.Errorf("flag %s requires a value", name)
+					}
+					value = args[i+1]
+					i++
+				}
+				c.readTimeout = value
+
+			case "strictFlag", "strict":
+				if hasValue {
+					b, err := strconv.ParseBool(value)
+					if err != nil {
+						return fmt.Errorf("invalid boolean value for flag %s: %s", name, value)
+					}
+					c.strictFlag = b
+				} else {
+					c.strictFlag = true
+				}
+			case "configFile", "config":
+				if !hasValue {
+					if i+1 >= len(args) {
+						return fmt.Errorf("flag %s requires a value", name)
+					}
+					value = args[i+1]
+					i++
+				}
+				c.configFile = value
+			case "thresholds", "threshold":
+				if !hasValue {
+					if i+1 >= len(args) {
+						return fmt.Errorf("flag %s requires a value", name)
+					}
+					value = args[i+1]
+					i++
+				}
+				c.thresholds = value
+			case "formats":
+				if !hasValue {
+					if i+1 >= len(args) {
+						return fmt.Errorf("flag %s requires a value", name)
+					}
+					value = args[i+1]
+					i++
+				}
+				c.formats = value
+			case "disableFormats", "disable-formats":
+				if !hasValue {
+					if i+1 >= len(args) {
+						return fmt.Errorf("flag %s requires a value", name)
+					}
+					value = args[i+1]
+					i++
+				}
+				c.disableFormats = value
+			case "ignorePatterns", "ignore":
+				if !hasValue {
+					if i+1 >= len(args) {
+						return fmt.Errorf("flag %s requires a value", name)
+					}
+					value = args[i+1]
+					i++
+				}
+				c.ignorePatterns = value
 			case "help", "h":
 				c.Usage()
 				return nil
@@ -132,14 +304,43 @@ func (c *RootCmd) NewGenerate() *Generate {
 	set.BoolVar(&v.saveFlag, "save", false, "Save the file as .editorconfig")
 	set.BoolVar(&v.saveFlag, "s", false, "Save the file as .editorconfig")
 
+	set.BoolVar(&v.mergeFlag, "merge", false, "Merge confident properties into the existing .editorconfig keeping everything else")
+
 	set.BoolVar(&v.verboseFlag, "verbose", false, "Logs more than what is required")
 	set.BoolVar(&v.verboseFlag, "v", false, "Logs more than what is required")
+
+	set.BoolVar(&v.confidenceFlag, "confidence", false, "Show the confidence of each property as a comment")
+
+	set.Float64Var(&v.minConfidence, "min-confidence", -1, "Leave out properties with a confidence below this (0 to 1), the config's if not given")
+
+	set.IntVar(&v.subdirectories, "subdirectories", -1, "How many directories deep to look for subtrees which differ from their parent, the config's if not given")
+
+	set.BoolVar(&v.nestedFlag, "nested", false, "Put subtree sections in their own nested .editorconfig files")
+
+	set.StringVar(&v.format, "format", "", "Output format: editorconfig, json or yaml, editorconfig unless the config sets it")
+
+	set.StringVar(&v.sampling, "sampling", "full", "Which parts of each file to survey: full, head or head-middle-tail")
+
+	set.IntVar(&v.jobs, "jobs", 0, "How many files to read at once, 0 uses every CPU")
+	set.IntVar(&v.jobs, "j", 0, "How many files to read at once, 0 uses every CPU")
+
+	set.StringVar(&v.readTimeout, "read-timeout", "", "Skip files which take longer than this to read, ie 5s")
+
+	set.BoolVar(&v.strictFlag, "strict", false, "Stop at the first file which can't be read instead of leaving it out")
+	set.StringVar(&v.configFile, "config", "", "Config file read after the user and project ones")
+
+	set.StringVar(&v.thresholds, "threshold", "", "Comma separated name=value thresholds, ie final_newline=0.9")
+
+	set.StringVar(&v.formats, "formats", "", "Comma separated file formats to run, every format if empty")
+
+	set.StringVar(&v.disableFormats, "disable-formats", "", "Comma separated file formats not to run")
+
+	set.StringVar(&v.ignorePatterns, "ignore", "", "Comma separated gitignore style patterns of files to leave out")
 	set.Usage = v.Usage
 
 	v.CommandAction = func(c *Generate) error {
 
-		cli.Generate(c.saveFlag, c.verboseFlag, c.args...)
-		return nil
+		return cli.Generate(c.saveFlag, c.mergeFlag, c.verboseFlag, c.confidenceFlag, c.minConfidence, c.subdirectories, c.nestedFlag, c.format, c.sampling, c.jobs, c.readTimeout, c.strictFlag, c.configFile, c.thresholds, c.formats, c.disableFormats, c.ignorePatterns, c.args...)
 	}
 
 	v.SubCommands["help"] = &InternalCommand{
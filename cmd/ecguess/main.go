@@ -1,5 +1,5 @@
-// Generated by github.com/arran4/go-subcommand/cmd/gosubc
-
+// Command ecguess guesses editorconfig files, see readme.md. The rest of the package is generated by gosubc from
+// internal/cli but main isn't, so that it exits with the codes exitCode picks; put it back if gosubc overwrites it.
 package main
 
 //go:generate sh -c "command -v gosubc >/dev/null 2>&1 && gosubc generate || go run github.com/arran4/go-subcommand/cmd/gosubc generate"
@@ -16,7 +16,7 @@ var (
 )
 
 func main() {
-	root, err := NewRoot("ecguess", version, commit, date)
+	root, err := newRoot("ecguess", version, commit, date)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
@@ -24,6 +24,6 @@ func main() {
 
 	if err := root.Execute(os.Args[1:]); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 }
@@ -72,7 +72,12 @@ func (c *RootCmd) UsageRecursive() {
 	fmt.Fprintf(os.Stderr, "Usage of %s:\n", os.Args[0])
 	c.PrintDefaults()
 	fmt.Fprintln(os.Stderr, "  Commands:")
+	fmt.Fprintf(os.Stderr, "    %s\n", "check")
+	fmt.Fprintf(os.Stderr, "    %s\n", "config")
+	fmt.Fprintf(os.Stderr, "    %s\n", "diff")
+	fmt.Fprintf(os.Stderr, "    %s\n", "fix")
 	fmt.Fprintf(os.Stderr, "    %s\n", "generate")
+	fmt.Fprintf(os.Stderr, "    %s\n", "outliers")
 }
 
 func NewRoot(name, version, commit, date string) (*RootCmd, error) {
@@ -91,7 +96,12 @@ func NewRoot(name, version, commit, date string) (*RootCmd, error) {
 		return nil
 	}
 
+	c.Commands["check"] = c.NewCheck()
+	c.Commands["config"] = c.NewConfig()
+	c.Commands["diff"] = c.NewDiff()
+	c.Commands["fix"] = c.NewFix()
 	c.Commands["generate"] = c.NewGenerate()
+	c.Commands["outliers"] = c.NewOutliers()
 	c.Commands["help"] = &InternalCommand{
 		Exec: func(args []string) error {
 			for _, arg := range args {
@@ -0,0 +1,169 @@
+package ecg
+
+import (
+	"sort"
+	"strings"
+)
+
+// PropertiesContraster the default Contraster, two results are equivalent (0) when their templates render the same
+// properties with the same values, comments and blank lines are ignored.
+func PropertiesContraster(s1, s2 *SummaryResult) int {
+	return strings.Compare(canonicalProperties(s1), canonicalProperties(s2))
+}
+
+// canonicalProperties the sorted `key=value` lines the result's template renders
+func canonicalProperties(sr *SummaryResult) string {
+	if sr == nil || sr.Template == nil {
+		return ""
+	}
+	ts, err := sr.Template.String()
+	if err != nil {
+		return ""
+	}
+	var kvs []string
+	for _, p := range ParseProperties(ts) {
+		if p.Key != "" {
+			kvs = append(kvs, p.Key+"="+p.Value)
+		}
+	}
+	sort.Strings(kvs)
+	return strings.Join(kvs, "\n")
+}
+
+// contrast compares the properties two sections render, sections scoped to a subdirectory only hold the properties
+// which diverge from their parent so their results can't tell them apart. A Contraster only has the last word when the
+// properties are the same.
+func contrast(s1, s2 *Section) int {
+	if s1.Result == nil || s2.Result == nil {
+		return -1
+	}
+	if c := strings.Compare(canonicalValues(s1), canonicalValues(s2)); c != 0 || s1.Result.Contaster == nil {
+		return c
+	}
+	return s1.Result.Contaster(s1.Result, s2.Result)
+}
+
+// canonicalValues the sorted `key=value` lines of the section
+func canonicalValues(s *Section) string {
+	var kvs []string
+	for k, v := range s.Values() {
+		kvs = append(kvs, k+"="+v)
+	}
+	sort.Strings(kvs)
+	return strings.Join(kvs, "\n")
+}
+
+// Consolidate drops the sections which only repeat what `[*]` already says and merges equivalent sections into a
+// single section with a brace glob, ie `[{*.ts,*.css}]`. Sections are only merged with others from the same path.
+// Sections of exact paths (see ExactGlob) only keep what differs from `[*]`.
+func Consolidate(sections []*Section) []*Section {
+	var all *Section
+	for _, s := range sections {
+		if s.Path == "/" && s.Header() == "*" {
+			all = s
+			break
+		}
+	}
+	result := make([]*Section, 0, len(sections))
+	for _, s := range sections {
+		if s != all && s.Path == "/" && all != nil && exactPaths(s) {
+			s = withoutInherited(s, all)
+		}
+		if s != all && s.Path == "/" && all != nil && redundant(s, all) {
+			continue
+		}
+		merged := false
+		for _, r := range result {
+			if r == all || r.Path != s.Path || contrast(r, s) != 0 {
+				continue
+			}
+			merge(r, s)
+			merged = true
+			break
+		}
+		if !merged {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// redundant true if every property the section has is already set to the same value in `[*]`
+func redundant(s, all *Section) bool {
+	values := all.Values()
+	for _, p := range s.Properties {
+		if p.Key == "" {
+			continue
+		}
+		if v, ok := values[p.Key]; !ok || v != p.Value {
+			return false
+		}
+	}
+	return true
+}
+
+// exactPaths true if every glob of the section is an exact path
+func exactPaths(s *Section) bool {
+	for _, g := range s.FileGlobs {
+		if !strings.HasPrefix(g, "/") {
+			return false
+		}
+	}
+	return len(s.FileGlobs) > 0
+}
+
+// withoutInherited a copy of the section without the properties and comments `[*]` already has
+func withoutInherited(s, all *Section) *Section {
+	values := all.Values()
+	comments := map[string]struct{}{}
+	for _, p := range all.Properties {
+		if p.IsComment() {
+			comments[p.Comment] = struct{}{}
+		}
+	}
+	result := *s
+	result.Properties = make([]*Property, 0, len(s.Properties))
+	for _, p := range s.Properties {
+		if v, ok := values[p.Key]; p.Key != "" && ok && v == p.Value {
+			continue
+		}
+		if _, ok := comments[p.Comment]; p.IsComment() && ok {
+			continue
+		}
+		result.Properties = append(result.Properties, p)
+	}
+	return &result
+}
+
+// merge adds s's globs to into, comments that differ between the two are dropped as they no longer apply to both
+func merge(into, s *Section) {
+	into.Format += ", " + s.Format
+	into.FileGlobs = append(append([]string{}, into.FileGlobs...), s.FileGlobs...)
+	comments := map[string]struct{}{}
+	for _, p := range s.Properties {
+		if p.IsComment() {
+			comments[p.Comment] = struct{}{}
+		}
+	}
+	properties := make([]*Property, 0, len(into.Properties))
+	for _, p := range into.Properties {
+		if p.IsComment() {
+			if _, ok := comments[p.Comment]; !ok {
+				continue
+			}
+		}
+		if p.Key != "" {
+			if o := s.Get(p.Key); o != nil && o.Confidence < p.Confidence {
+				c := *p
+				c.Confidence = o.Confidence
+				p = &c
+			}
+		}
+		properties = append(properties, p)
+	}
+	into.Properties = properties
+	if s.Confidence < into.Confidence {
+		into.Confidence = s.Confidence
+	}
+	into.Merged = append(into.Merged, s)
+}
@@ -0,0 +1,85 @@
+package ecg
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func newTestSection(t *testing.T, format string, template string, globs ...string) *Section {
+	t.Helper()
+	s, err := NewSection(format, &SummaryResult{
+		FileGlobs:  globs,
+		Confidence: 1,
+		Template:   ErrorStringerWrapper(bytes.NewBufferString(template)),
+		Path:       "/",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestConsolidate(t *testing.T) {
+	all := newTestSection(t, "All Files", "insert_final_newline = true\nend_of_line = lf\n", "*")
+	ts := newTestSection(t, "TypeScript", "# charset = ???\nend_of_line = lf\nindent_style = space\nindent_size = 2\n", "*.ts", "*.js")
+	css := newTestSection(t, "CSS", "# charset = ??? utf-8 (20%)\nindent_size = 2\nend_of_line = lf\nindent_style = space\n", "*.css")
+	md := newTestSection(t, "Markdown", "# charset = ???\nend_of_line = lf\n", "*.md")
+	goSection := newTestSection(t, "Go", "indent_style = tab\n", "*.go")
+	scoped := newTestSection(t, "TypeScript", "end_of_line = lf\n", "frontend/**.ts")
+	scoped.Path = "/frontend"
+	got := Consolidate([]*Section{all, ts, css, md, goSection, scoped})
+	var headers []string
+	for _, s := range got {
+		headers = append(headers, s.Header())
+	}
+	if diff := cmp.Diff([]string{"*", "{*.ts,*.js,*.css}", "*.go", "frontend/**.ts"}, headers); diff != "" {
+		t.Errorf("Consolidate() headers mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff("[{*.ts,*.js,*.css}]\nend_of_line = lf\nindent_style = space\nindent_size = 2\n\n", got[1].String()); diff != "" {
+		t.Errorf("Consolidate() merged section mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestConsolidate_ExactPaths(t *testing.T) {
+	all := newTestSection(t, "All Files", "# charset = ???\ninsert_final_newline = true\nend_of_line = lf\n", "*")
+	deploy := newTestSection(t, "Shell", "# charset = ???\ninsert_final_newline = true\nend_of_line = lf\nindent_style = tabs\n", "/bin/deploy")
+	tool := newTestSection(t, "Python", "insert_final_newline = true\n", "/bin/tool")
+	got := Consolidate([]*Section{all, deploy, tool})
+	if len(got) != 2 {
+		t.Fatalf("Consolidate() returned %d sections, want [*] and [/bin/deploy]", len(got))
+	}
+	if diff := cmp.Diff("[/bin/deploy]\nindent_style = tabs\n\n", got[1].String()); diff != "" {
+		t.Errorf("Consolidate() exact path section mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestConsolidate_ScopedDivergence(t *testing.T) {
+	result := newTestSection(t, "TypeScript", "end_of_line = lf\nindent_style = space\nindent_size = 2\n", "*.ts").Result
+	tabs := &Section{Format: "TypeScript", FileGlobs: []string{"frontend/**.ts"}, Path: "/frontend", Result: result,
+		Properties: []*Property{{Key: "indent_style", Value: "tab"}}}
+	crlf := &Section{Format: "TypeScript", FileGlobs: []string{"frontend/**.tsx"}, Path: "/frontend", Result: result,
+		Properties: []*Property{{Key: "end_of_line", Value: "crlf"}}}
+	got := Consolidate([]*Section{tabs, crlf})
+	if len(got) != 2 {
+		t.Fatalf("Consolidate() merged sections which diverge on different keys:\n%s", got[0])
+	}
+}
+
+func TestConsolidate_Spacing(t *testing.T) {
+	all := newTestSection(t, "All Files", "end_of_line = lf\n", "*")
+	tf := newTestSection(t, "Generic", "indent_size = 2\n", "*.tf")
+	proto := newTestSection(t, "Generic", "indent_size = 2\n", "*.proto")
+	sql := newTestSection(t, "Generic", "end_of_line = lf\n", "*.sql")
+	sh := newTestSection(t, "Generic", "indent_size = 4\n", "*.sh")
+	for _, s := range []*Section{proto, sql, sh} {
+		s.Spaced = true
+	}
+	// [*.proto] is merged and [*.sql] dropped, neither leaves its spacing behind
+	g := &Guess{Sections: Consolidate([]*Section{all, tf, proto, sql, sh})}
+	want := "[*]\nend_of_line = lf\n\n[{*.tf,*.proto}]\nindent_size = 2\n\n\n\n[*.sh]\nindent_size = 4\n\n"
+	if diff := cmp.Diff(want, g.String()); diff != "" {
+		t.Errorf("String() mismatch (-want +got):\n%s", diff)
+	}
+}
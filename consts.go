@@ -5,4 +5,12 @@ import "github.com/alecthomas/units"
 const (
 // ReadSize ...
 	ReadSize = 256 * units.Kibibyte
+	// lineLengthStep the granularity max_line_length is guessed at
+	lineLengthStep = 20
+	// DefaultSubdirectoryMinFiles the fewest files a subdirectory needs before it can diverge from its parent
+	DefaultSubdirectoryMinFiles = 3
+	// divergenceConfidence the confidence a subdirectory's property needs before it can diverge from its parent
+	divergenceConfidence = 0.5
+	// ContainerQueueSize how many files can wait for a Container before the workers sending them block
+	ContainerQueueSize = 256
 )
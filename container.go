@@ -1,7 +1,7 @@
 package ecg
 
 import (
-	"fmt"
+	"errors"
 	"sync"
 )
 
@@ -24,7 +24,7 @@ func NewContainer(name string, fr FileRunner) *Container {
 type Container struct {
 	sync.WaitGroup
 	reader  chan *File
-	errors  []error
+	errors  []*FileError
 	summary []*SummaryResult
 	name    string
 	FileRunner
@@ -37,7 +37,7 @@ func (l *Container) Name() string {
 
 // Start ...
 func (l *Container) Start() chan *File {
-	l.reader = make(chan *File)
+	l.reader = make(chan *File, ContainerQueueSize)
 	l.Add(1)
 	go l.Run()
 	return l.reader
@@ -53,7 +53,7 @@ func (l *Container) Done() ([]*SummaryResult, error) {
 func (l *Container) Run() {
 	defer l.WaitGroup.Done()
 	if sr, err := l.Init(); err != nil {
-		l.errors = append(l.errors, err)
+		l.fail("", err)
 	} else if len(sr) > 0 {
 		l.summary = append(l.summary, sr...)
 	}
@@ -64,21 +64,32 @@ func (l *Container) Run() {
 			break
 		}
 		if sr, err := l.RunFile(f); err != nil {
-			l.errors = append(l.errors, err)
+			l.fail(f.Filename, err)
 		} else if len(sr) > 0 {
 			l.summary = append(l.summary, sr...)
 		}
 	}
 	if sr, err := l.End(); err != nil {
-		l.errors = append(l.errors, err)
+		l.fail("", err)
 	} else if len(sr) > 0 {
 		l.summary = append(l.summary, sr...)
 	}
 }
 
+// fail records an error, filename is "" when it isn't about a single file
+func (l *Container) fail(filename string, err error) {
+	l.errors = append(l.errors, &FileError{
+		Filename: filename,
+		Format:   l.Name(),
+		Err:      err,
+	})
+}
+
+// error every error joined together, each is a *FileError
 func (l *Container) error() error {
-	if len(l.errors) == 0 {
-		return nil
+	errs := make([]error, 0, len(l.errors))
+	for _, e := range l.errors {
+		errs = append(errs, e)
 	}
-	return fmt.Errorf("%s errors: %w", l.Name(), l.errors[0])
+	return errors.Join(errs...)
 }
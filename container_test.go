@@ -0,0 +1,43 @@
+package ecg
+
+import (
+	"errors"
+	"testing"
+)
+
+// failingRunner fails on every file
+type failingRunner struct{}
+
+func (failingRunner) Init() ([]*SummaryResult, error) {
+	return nil, nil
+}
+
+func (failingRunner) RunFile(f *File) ([]*SummaryResult, error) {
+	return nil, errors.New("failed")
+}
+
+func (failingRunner) End() ([]*SummaryResult, error) {
+	return nil, errors.New("no summary")
+}
+
+func TestContainer_Errors(t *testing.T) {
+	c := NewContainer("Failing", failingRunner{})
+	ch := c.Start()
+	ch <- &File{Filename: "a.txt"}
+	ch <- &File{Filename: "b.txt"}
+	ch <- nil
+	_, err := c.Done()
+	var got []string
+	for _, e := range splitErrors(c.Name(), err) {
+		got = append(got, e.Error())
+	}
+	want := []string{"a.txt: Failing: failed", "b.txt: Failing: failed", "Failing: no summary"}
+	if len(got) != len(want) {
+		t.Fatalf("Done() errors = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Done() errors[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package ecg
+
+import (
+	"io"
+)
+
+// ContentSurveyor surveys the content of a file as it is read for its FileSurvey, for FileFormats which need more than
+// the LineSurvey without reading the file a second time. See RegisterContentSurveyor
+type ContentSurveyor interface {
+	// Write is given the parts of the file the SamplePolicy picks, in order
+	io.Writer
+	// Break the next Write doesn't carry on from the last, the part of the file in between isn't sampled
+	Break()
+	// End the file has been read
+	End()
+}
+
+// contentSurveyorFactory see RegisterContentSurveyor
+type contentSurveyorFactory struct {
+	name  string
+	wants func(filename, filetype string) bool
+	new   func() ContentSurveyor
+}
+
+var (
+	contentSurveyors []*contentSurveyorFactory
+)
+
+// RegisterContentSurveyor surveys the files wants is true for with a ContentSurveyor from new as they are read, see
+// FileSurvey.Content. wants is given the file's name and what File.Filetype returns for it.
+func RegisterContentSurveyor(name string, wants func(filename, filetype string) bool, new func() ContentSurveyor) {
+	contentSurveyors = append(contentSurveyors, &contentSurveyorFactory{name: name, wants: wants, new: new})
+}
+
+// newContentSurveyors the ContentSurveyors which want the file, keyed by the name they were registered with
+func newContentSurveyors(filename, filetype string) map[string]ContentSurveyor {
+	var result map[string]ContentSurveyor
+	for _, csf := range contentSurveyors {
+		if !csf.wants(filename, filetype) {
+			continue
+		}
+		if result == nil {
+			result = map[string]ContentSurveyor{}
+		}
+		result[csf.name] = csf.new()
+	}
+	return result
+}
+
+// Content what the ContentSurveyor registered under name made of the file, nil if it didn't want the file
+func (s *FileSurvey) Content(name string) ContentSurveyor {
+	return s.content[name]
+}
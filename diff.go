@@ -0,0 +1,88 @@
+package ecg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Disagreement a property where the declared editorconfig and the guess differ
+type Disagreement struct {
+	// Header of the guessed section, ie `*.go`
+	Section string
+	// The glob of the section the declaration is for, only set when the section has several, ie `*.ts` of
+	// `{*.js,*.ts}`
+	Glob     string
+	Property string
+	// The value the editorconfig files give, empty if they don't set it
+	Declared string
+	Guessed  string
+	// Confidence of the guessed value
+	Confidence float64
+}
+
+// String ...
+func (d *Disagreement) String() string {
+	declared := "file doesn't set it"
+	if d.Declared != "" {
+		declared = "file says " + d.Declared
+	}
+	if d.Glob != "" {
+		declared += " for " + d.Glob
+	}
+	return fmt.Sprintf("[%s] %s: %s, code looks like %s with %0.0f%% confidence", d.Section, d.Property, declared, d.Guessed, d.Confidence*100)
+}
+
+// Diff compares every property of the guess with what the editorconfig files declare for the same files, for each glob
+// of a section as the files may declare them differently. Guessed properties with a confidence below minConfidence are
+// not reported.
+func Diff(configs EditorConfigs, guess *Guess, minConfidence float64) []*Disagreement {
+	var result []*Disagreement
+	for _, s := range guess.Sections {
+		declared := make([]map[string]string, len(s.FileGlobs))
+		for i, g := range s.FileGlobs {
+			declared[i] = configs.PropertiesFor(RepresentativePath(g))
+		}
+		for _, p := range s.Properties {
+			if p.Key == "" || p.Confidence < minConfidence {
+				continue
+			}
+			for i, g := range s.FileGlobs {
+				if normalizeValue(declared[i][p.Key]) == normalizeValue(p.Value) {
+					continue
+				}
+				d := &Disagreement{
+					Section:    s.Header(),
+					Property:   p.Key,
+					Declared:   declared[i][p.Key],
+					Guessed:    p.Value,
+					Confidence: p.Confidence,
+				}
+				if len(s.FileGlobs) > 1 {
+					d.Glob = g
+				}
+				result = append(result, d)
+			}
+		}
+	}
+	return result
+}
+
+// RepresentativePath a made up path the glob matches, ie `*.go` gives `example.go`
+func RepresentativePath(glob string) string {
+	glob = strings.TrimPrefix(glob, "/")
+	glob = strings.ReplaceAll(glob, "**", "*")
+	glob = strings.ReplaceAll(glob, "*", "example")
+	return strings.ReplaceAll(glob, "?", "x")
+}
+
+// normalizeValue values are case-insensitive, and the guesser's plural indent styles mean the same as the singular
+func normalizeValue(v string) string {
+	v = strings.ToLower(v)
+	switch v {
+	case "spaces":
+		return "space"
+	case "tabs":
+		return "tab"
+	}
+	return v
+}
@@ -0,0 +1,64 @@
+package ecg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDiff(t *testing.T) {
+	declared, err := ParseEditorConfig(strings.NewReader("root = true\n[*]\nend_of_line = lf\n[*.go]\nindent_size = 2\nindent_style = tab\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	guess := &Guess{
+		Sections: []*Section{
+			{FileGlobs: []string{"*"}, Properties: []*Property{
+				{Key: "end_of_line", Value: "lf", Confidence: 1},
+				{Key: "insert_final_newline", Value: "true", Confidence: 0.9},
+			}},
+			{FileGlobs: []string{"*.go"}, Properties: []*Property{
+				{Comment: "charset = ???"},
+				{Key: "indent_style", Value: "tabs", Confidence: 0.99},
+				{Key: "indent_size", Value: "4", Confidence: 0.93},
+				{Key: "max_line_length", Value: "80", Confidence: 0.2},
+			}},
+		},
+	}
+	var got []string
+	for _, d := range Diff(EditorConfigs{".": declared}, guess, 0.5) {
+		got = append(got, d.String())
+	}
+	want := []string{
+		"[*] insert_final_newline: file doesn't set it, code looks like true with 90% confidence",
+		"[*.go] indent_size: file says 2, code looks like 4 with 93% confidence",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Diff() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestDiff_ConsolidatedSection(t *testing.T) {
+	declared, err := ParseEditorConfig(strings.NewReader("root = true\n[*.{js,ts}]\nindent_size = 2\n[*.ts]\nindent_size = 4\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	guess := &Guess{
+		Sections: []*Section{
+			{FileGlobs: []string{"*.js", "*.ts"}, Properties: []*Property{
+				{Key: "indent_size", Value: "2", Confidence: 0.9},
+			}},
+		},
+	}
+	var got []string
+	for _, d := range Diff(EditorConfigs{".": declared}, guess, 0.5) {
+		got = append(got, d.String())
+	}
+	want := []string{
+		"[{*.js,*.ts}] indent_size: file says 4 for *.ts, code looks like 2 with 90% confidence",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Diff() mismatch (-want +got):\n%s", diff)
+	}
+}
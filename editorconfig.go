@@ -0,0 +1,306 @@
+package ecg
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// EditorConfigFilename the name editorconfig files are looked up by
+const EditorConfigFilename = ".editorconfig"
+
+// ParseEditorConfig parses an editorconfig file into a Guess, comments, blank lines and unknown properties are kept so
+// it renders back out unchanged. Every property has a confidence of 1.
+func ParseEditorConfig(r io.Reader) (*Guess, error) {
+	g := &Guess{}
+	var current *Section
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if header, ok := parseSectionHeader(line); ok {
+			current = &Section{
+				FileGlobs:  splitHeader(header),
+				Path:       "/",
+				Confidence: 1,
+			}
+			g.Sections = append(g.Sections, current)
+			continue
+		}
+		p := ParseProperty(line)
+		if p.Key != "" {
+			p.Confidence = 1
+		}
+		if current == nil {
+			g.Preamble = append(g.Preamble, p)
+		} else {
+			current.Properties = append(current.Properties, p)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading editorconfig: %w", err)
+	}
+	return g, nil
+}
+
+// parseSectionHeader returns the glob between the square brackets of a `[glob]` line
+func parseSectionHeader(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "[") || !strings.HasSuffix(line, "]") {
+		return "", false
+	}
+	return line[1 : len(line)-1], true
+}
+
+// splitHeader splits `{a,b}` into its globs when the whole header is a single brace group, so it renders back the same
+func splitHeader(header string) []string {
+	if !strings.HasPrefix(header, "{") || !strings.HasSuffix(header, "}") {
+		return []string{header}
+	}
+	inner := header[1 : len(header)-1]
+	if strings.ContainsAny(inner, "{}") || !strings.Contains(inner, ",") {
+		return []string{header}
+	}
+	return strings.Split(inner, ",")
+}
+
+// IsRoot true if the preamble has `root = true`
+func (g *Guess) IsRoot() bool {
+	for _, p := range g.Preamble {
+		if p.Key == "root" && strings.EqualFold(p.Value, "true") {
+			return true
+		}
+	}
+	return false
+}
+
+// PropertiesFor the effective properties for a slash separated path relative to the editorconfig file, later sections
+// override earlier ones
+func (g *Guess) PropertiesFor(filename string) map[string]string {
+	result := map[string]string{}
+	for _, s := range g.Sections {
+		if !MatchGlob(s.Header(), filename) {
+			continue
+		}
+		for k, v := range s.Values() {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// EditorConfigs the editorconfig files found in a directory tree keyed by the directory they are in, "." is the top
+type EditorConfigs map[string]*Guess
+
+// LoadEditorConfigs reads every .editorconfig file in dir, a WalkFilter (see WithWalkFilter) only prunes directories
+// as the files are found by name
+func LoadEditorConfigs(dir fs.FS, opts ...Option) (EditorConfigs, error) {
+	result := EditorConfigs{}
+	o := newAnalyzeOptions(opts)
+	err := fs.WalkDir(dir, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if skip, err := o.skip(p, d); skip {
+				return err
+			}
+			return nil
+		}
+		if d.Name() != EditorConfigFilename {
+			return nil
+		}
+		f, err := dir.Open(p)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+		g, err := ParseEditorConfig(f)
+		if err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+		result[path.Dir(p)] = g
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// PropertiesFor the effective properties of a slash separated path, files closer to the path override those further
+// up, and a file with `root = true` stops the search
+func (ecs EditorConfigs) PropertiesFor(filename string) map[string]string {
+	var dirs []string
+	for d := path.Dir(filename); ; d = path.Dir(d) {
+		if g, ok := ecs[d]; ok {
+			dirs = append(dirs, d)
+			if g.IsRoot() {
+				break
+			}
+		}
+		if d == "." || d == "/" {
+			break
+		}
+	}
+	result := map[string]string{}
+	for i := len(dirs) - 1; i >= 0; i-- {
+		rel := filename
+		if dirs[i] != "." {
+			rel = strings.TrimPrefix(filename, dirs[i]+"/")
+		}
+		for k, v := range ecs[dirs[i]].PropertiesFor(rel) {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+var errBadGlob = errors.New("bad glob")
+
+// MatchGlob reports if a slash separated path relative to the editorconfig file matches an editorconfig section glob.
+// Globs without a `/` match at any depth. Invalid globs match nothing.
+func MatchGlob(glob, filename string) bool {
+	re, ranges, err := globRegexp(glob)
+	if err != nil {
+		return false
+	}
+	m := re.FindStringSubmatch(strings.TrimPrefix(filename, "/"))
+	if m == nil {
+		return false
+	}
+	for i, r := range ranges {
+		n, err := strconv.Atoi(m[i+1])
+		if err != nil || n < r[0] || n > r[1] {
+			return false
+		}
+	}
+	return true
+}
+
+var numericRange = regexp.MustCompile(`^([+-]?\d+)\.\.([+-]?\d+)$`)
+
+// globRegexp converts an editorconfig glob into a regular expression, `{n..m}` ranges become capture groups which have
+// to be checked against the returned bounds
+func globRegexp(glob string) (*regexp.Regexp, [][2]int, error) {
+	sb := &strings.Builder{}
+	var ranges [][2]int
+	anchored := strings.Contains(glob, "/")
+	glob = strings.TrimPrefix(glob, "/")
+	if anchored {
+		sb.WriteString("^")
+	} else {
+		sb.WriteString("^(?:.*/)?")
+	}
+	braces := 0
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch c {
+		case '\\':
+			if i+1 < len(glob) {
+				i++
+				sb.WriteString(regexp.QuoteMeta(string(glob[i])))
+			}
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				i++
+				if i+1 < len(glob) && glob[i+1] == '/' && (i-1 == 0 || glob[i-2] == '/') {
+					// `**/` also matches no directories at all
+					i++
+					sb.WriteString("(?:.*/)?")
+				} else {
+					sb.WriteString(".*")
+				}
+			} else {
+				sb.WriteString("[^/]*")
+			}
+		case '?':
+			sb.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				sb.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			i += end + 1
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+		case '{':
+			end := matchingBrace(glob, i)
+			if end < 0 {
+				sb.WriteString(`\{`)
+				continue
+			}
+			inner := glob[i+1 : end]
+			if m := numericRange.FindStringSubmatch(inner); m != nil {
+				lo, _ := strconv.Atoi(m[1])
+				hi, _ := strconv.Atoi(m[2])
+				ranges = append(ranges, [2]int{lo, hi})
+				sb.WriteString(`([+-]?\d+)`)
+				i = end
+				continue
+			}
+			if !strings.Contains(inner, ",") {
+				// A single choice is taken literally
+				sb.WriteString(regexp.QuoteMeta("{" + inner + "}"))
+				i = end
+				continue
+			}
+			braces++
+			sb.WriteString("(?:")
+		case '}':
+			if braces == 0 {
+				sb.WriteString(`\}`)
+				continue
+			}
+			braces--
+			sb.WriteString(")")
+		case ',':
+			if braces == 0 {
+				sb.WriteString(",")
+				continue
+			}
+			sb.WriteString("|")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	if braces != 0 {
+		return nil, nil, errBadGlob
+	}
+	sb.WriteString("$")
+	re, err := regexp.Compile(sb.String())
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", errBadGlob, err)
+	}
+	return re, ranges, nil
+}
+
+// matchingBrace the index of the `}` closing the `{` at start or -1
+func matchingBrace(glob string, start int) int {
+	depth := 0
+	for i := start; i < len(glob); i++ {
+		switch glob[i] {
+		case '\\':
+			i++
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
@@ -0,0 +1,106 @@
+package ecg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		glob     string
+		filename string
+		want     bool
+	}{
+		{glob: "*", filename: "a.go", want: true},
+		{glob: "*", filename: "src/a.go", want: true},
+		{glob: "*.go", filename: "src/deep/a.go", want: true},
+		{glob: "*.go", filename: "a.go.txt", want: false},
+		{glob: "Makefile", filename: "sub/Makefile", want: true},
+		{glob: "{*.ts,*.js}", filename: "a.js", want: true},
+		{glob: "*.{ts,js}", filename: "a.ts", want: true},
+		{glob: "*.{ts,js}", filename: "a.css", want: false},
+		{glob: "{single}", filename: "{single}", want: true},
+		{glob: "a?c", filename: "abc", want: true},
+		{glob: "a?c", filename: "a/c", want: false},
+		{glob: "[ab].txt", filename: "b.txt", want: true},
+		{glob: "[!ab].txt", filename: "b.txt", want: false},
+		{glob: "file{1..3}.txt", filename: "file2.txt", want: true},
+		{glob: "file{1..3}.txt", filename: "file4.txt", want: false},
+		{glob: "frontend/**.ts", filename: "frontend/a.ts", want: true},
+		{glob: "frontend/**.ts", filename: "frontend/lib/a.ts", want: true},
+		{glob: "frontend/**.ts", filename: "backend/frontend/a.ts", want: false},
+		{glob: "/lib/*.c", filename: "lib/a.c", want: true},
+		{glob: "lib/*.c", filename: "lib/sub/a.c", want: false},
+		{glob: "**/vendor/*.c", filename: "vendor/a.c", want: true},
+		{glob: "{unclosed", filename: "{unclosed", want: true},
+		{glob: "{a,b", filename: "a", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.glob+" "+tt.filename, func(t *testing.T) {
+			if got := MatchGlob(tt.glob, tt.filename); got != tt.want {
+				t.Errorf("MatchGlob(%q, %q) = %v, want %v", tt.glob, tt.filename, got, tt.want)
+			}
+		})
+	}
+}
+
+const testEditorConfig = `# A comment
+root = true
+
+[*]
+indent_style = space
+ij_smart_tabs = false
+
+; Go
+[{*.go,go.mod}]
+indent_style = tab # gofmt
+`
+
+func TestParseEditorConfig(t *testing.T) {
+	g, err := ParseEditorConfig(strings.NewReader(testEditorConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g.IsRoot() {
+		t.Errorf("IsRoot() = false, want true")
+	}
+	if diff := cmp.Diff(testEditorConfig, g.String()); diff != "" {
+		t.Errorf("String() round trip mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(map[string]string{"indent_style": "tab", "ij_smart_tabs": "false"}, g.PropertiesFor("cmd/main.go")); diff != "" {
+		t.Errorf("PropertiesFor() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestEditorConfigs_PropertiesFor(t *testing.T) {
+	parse := func(s string) *Guess {
+		g, err := ParseEditorConfig(strings.NewReader(s))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return g
+	}
+	ecs := EditorConfigs{
+		".":           parse("root = true\n[*]\nindent_style = tab\nend_of_line = lf\n"),
+		"web":         parse("[*.ts]\nindent_style = space\n"),
+		"web/vendor":  parse("root = true\n[*]\ncharset = latin1\n"),
+		"unrelated/x": parse("[*]\nindent_style = space\n"),
+	}
+	tests := []struct {
+		filename string
+		want     map[string]string
+	}{
+		{filename: "main.go", want: map[string]string{"indent_style": "tab", "end_of_line": "lf"}},
+		{filename: "web/app/a.ts", want: map[string]string{"indent_style": "space", "end_of_line": "lf"}},
+		{filename: "web/vendor/a.ts", want: map[string]string{"charset": "latin1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, ecs.PropertiesFor(tt.filename)); diff != "" {
+				t.Errorf("PropertiesFor() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package ecg
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// FileError a file which couldn't be surveyed, or a FileFormat which failed. Analyze carries on without them and
+// lists them in Guess.Errors unless WithStrict is used.
+type FileError struct {
+	// The file, "" if the FileFormat failed as a whole
+	Filename string
+	// The FileFormat which failed, "" if the file couldn't be read at all
+	Format string
+	Err    error
+}
+
+// Error ie `main.go: Go: permission denied`, the filename is left out if the error already names it as fs.PathError does
+func (e *FileError) Error() string {
+	s := e.Err.Error()
+	if e.Format != "" {
+		s = e.Format + ": " + s
+	}
+	if e.Filename != "" && !strings.Contains(s, e.Filename) {
+		s = e.Filename + ": " + s
+	}
+	return s
+}
+
+// Unwrap ...
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+// fileErrors collects FileErrors from several goroutines
+type fileErrors struct {
+	sync.Mutex
+	errors []*FileError
+}
+
+func (fe *fileErrors) add(e ...*FileError) {
+	fe.Lock()
+	defer fe.Unlock()
+	fe.errors = append(fe.errors, e...)
+}
+
+// sorted the errors by filename then format
+func (fe *fileErrors) sorted() []*FileError {
+	fe.Lock()
+	defer fe.Unlock()
+	result := append([]*FileError(nil), fe.errors...)
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Filename != result[j].Filename {
+			return result[i].Filename < result[j].Filename
+		}
+		return result[i].Format < result[j].Format
+	})
+	return result
+}
+
+// splitErrors breaks up the errors a FileFormat joined together, those which aren't FileErrors are put down to the
+// format as a whole
+func splitErrors(format string, err error) []*FileError {
+	if err == nil {
+		return nil
+	}
+	var fe *FileError
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var result []*FileError
+		for _, e := range joined.Unwrap() {
+			result = append(result, splitErrors(format, e)...)
+		}
+		return result
+	}
+	if errors.As(err, &fe) {
+		return []*FileError{fe}
+	}
+	return []*FileError{{Format: format, Err: err}}
+}
+
+// Warning something a FileFormat noticed about a file which doesn't belong in the editorconfig file, ie a makefile
+// recipe indented with spaces. Analyze lists them in Guess.Warnings
+type Warning struct {
+	Filename string `json:"filename" yaml:"filename"`
+	// The line of the file, 0 if it isn't about a line or it isn't known
+	Line int `json:"line,omitempty" yaml:"line,omitempty"`
+	// The FileFormat which noticed, Analyze fills it in
+	Format  string `json:"format,omitempty" yaml:"format,omitempty"`
+	Message string `json:"message" yaml:"message"`
+}
+
+// String ie `Makefile:4: GNU Make: recipe is indented with spaces`
+func (w *Warning) String() string {
+	s := w.Message
+	if w.Format != "" {
+		s = w.Format + ": " + s
+	}
+	if w.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", w.Filename, w.Line, s)
+	}
+	return w.Filename + ": " + s
+}
+
+// sortWarnings by filename then line
+func sortWarnings(warnings []*Warning) {
+	sort.SliceStable(warnings, func(i, j int) bool {
+		if warnings[i].Filename != warnings[j].Filename {
+			return warnings[i].Filename < warnings[j].Filename
+		}
+		return warnings[i].Line < warnings[j].Line
+	})
+}
@@ -0,0 +1,123 @@
+package ecg_test
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	ecg "editorconfig-guesser"
+	_ "editorconfig-guesser/fileformats"
+)
+
+// failingFS fails to open some of the files
+type failingFS struct {
+	fs.FS
+	fail map[string]error
+}
+
+func (f *failingFS) Open(name string) (fs.File, error) {
+	if err, ok := f.fail[name]; ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	return f.FS.Open(name)
+}
+
+func TestAnalyzeErrors(t *testing.T) {
+	dir := &failingFS{
+		FS: fstest.MapFS{
+			"main.go":   &fstest.MapFile{Data: []byte("package main\n\nfunc main() {\n\tprintln()\n}\n")},
+			"locked.go": &fstest.MapFile{Data: []byte("package main\n")},
+			"gone.go":   &fstest.MapFile{Data: []byte("package main\n")},
+		},
+		fail: map[string]error{
+			"locked.go": fs.ErrPermission,
+			"gone.go":   fs.ErrNotExist,
+		},
+	}
+	ignore := func(f *ecg.File) bool { return false }
+	guess, err := ecg.Analyze(dir, ignore)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if guess.Section("*.go") == nil {
+		t.Errorf("Analyze() left out the files which could be read:\n%s", guess)
+	}
+	if guess.Files != 1 {
+		t.Errorf("Files = %d, want 1", guess.Files)
+	}
+	if len(guess.Errors) != 2 {
+		t.Fatalf("Errors = %v, want 2", guess.Errors)
+	}
+	if e := guess.Errors[0]; e.Filename != "gone.go" || !errors.Is(e, fs.ErrNotExist) {
+		t.Errorf("Errors[0] = %v, want gone.go not existing", e)
+	}
+	if e := guess.Errors[1]; e.Filename != "locked.go" || !errors.Is(e, fs.ErrPermission) {
+		t.Errorf("Errors[1] = %v, want locked.go permission denied", e)
+	}
+	if got, want := guess.Errors[1].Error(), "opening locked.go: open locked.go: permission denied"; got != want {
+		t.Errorf("Errors[1].Error() = %q, want %q", got, want)
+	}
+
+	_, err = ecg.Analyze(dir, ignore, ecg.WithStrict())
+	var fe *ecg.FileError
+	if !errors.As(err, &fe) {
+		t.Fatalf("Analyze() with WithStrict error = %v, want a FileError", err)
+	}
+}
+
+func TestCheckErrors(t *testing.T) {
+	dir := &failingFS{
+		FS: fstest.MapFS{
+			".editorconfig": &fstest.MapFile{Data: []byte("root = true\n[*]\nend_of_line = lf\n")},
+			"a.go":          &fstest.MapFile{Data: []byte("package a\r\n")},
+			"locked.go":     &fstest.MapFile{Data: []byte("package main\n")},
+			"z.go":          &fstest.MapFile{Data: []byte("package z\r\n")},
+		},
+		fail: map[string]error{"locked.go": fs.ErrPermission},
+	}
+	configs, err := ecg.LoadEditorConfigs(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ignore := func(f *ecg.File) bool { return false }
+	violations, err := ecg.Check(dir, configs, ignore)
+	if len(violations) != 2 || violations[0].Filename != "a.go" || violations[1].Filename != "z.go" {
+		t.Errorf("Check() violations = %v, want a.go and z.go", violations)
+	}
+	var fe *ecg.FileError
+	if !errors.As(err, &fe) || fe.Filename != "locked.go" || !errors.Is(err, fs.ErrPermission) {
+		t.Errorf("Check() error = %v, want locked.go permission denied", err)
+	}
+	if violations, err := ecg.Check(dir, configs, ignore, ecg.WithStrict()); err == nil || len(violations) != 1 {
+		t.Errorf("Check() with WithStrict() = %v, %v; want it to stop at locked.go", violations, err)
+	}
+}
+
+func TestFixesErrors(t *testing.T) {
+	dir := &failingFS{
+		FS: fstest.MapFS{
+			".editorconfig": &fstest.MapFile{Data: []byte("root = true\n[*]\nend_of_line = lf\n")},
+			"a.go":          &fstest.MapFile{Data: []byte("package a\r\n")},
+			"locked.go":     &fstest.MapFile{Data: []byte("package main\r\n")},
+			"z.go":          &fstest.MapFile{Data: []byte("package z\r\n")},
+		},
+		fail: map[string]error{"locked.go": fs.ErrPermission},
+	}
+	configs, err := ecg.LoadEditorConfigs(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ignore := func(f *ecg.File) bool { return false }
+	fixes, err := ecg.Fixes(dir, configs, ignore)
+	if len(fixes) != 2 || fixes[0].Filename != "a.go" || fixes[1].Filename != "z.go" {
+		t.Errorf("Fixes() = %v, want a.go and z.go fixed", fixes)
+	}
+	var fe *ecg.FileError
+	if !errors.As(err, &fe) || fe.Filename != "locked.go" || !errors.Is(err, fs.ErrPermission) {
+		t.Errorf("Fixes() error = %v, want locked.go permission denied", err)
+	}
+	if fixes, err := ecg.Fixes(dir, configs, ignore, ecg.WithStrict()); err == nil || len(fixes) != 1 {
+		t.Errorf("Fixes() with WithStrict() = %v, %v; want it to stop at locked.go", fixes, err)
+	}
+}
@@ -0,0 +1,202 @@
+package ecg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"sync"
+
+	"github.com/alecthomas/units"
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/saintfish/chardet"
+)
+
+// File reference, it caches what is read from the file so it is only read once however many FileFormats it is sent to
+type File struct {
+	Filename   string
+	FileOpener fs.FS
+	size       *int64
+	sync.Mutex
+	// Which parts of the file BasicSurveyor.ReadFile surveys
+	Sampling SamplePolicy
+
+	// The first ReadSize bytes, dropped once the file is surveyed
+	head       []byte
+	headSize   int64
+	headErr    error
+	headOnce   sync.Once
+	binary     bool
+	binaryOnce sync.Once
+	survey     *FileSurvey
+	surveyErr  error
+	surveyOnce sync.Once
+}
+
+// FileSurvey what reading a file found, shared by every BasicSurveyor the file is given to
+type FileSurvey struct {
+	// Charset as editorconfig names it if it can, "" if it couldn't be detected or the file is pure ASCII
+	Charset string
+	// The detector's confidence in the charset (0 to 100)
+	CharsetConfidence int
+	// The file starts with a byte order mark
+	BOM bool
+	// The file is pure ASCII
+	ASCII        bool
+	FinalNewLine bool
+	Lines        *LineSurvey
+	// What a file without an extension is written in, see File.Filetype
+	Filetype string
+	// See Content
+	content map[string]ContentSurveyor
+}
+
+// Size of file + cache
+func (fd *File) Size() int64 {
+	fd.Lock()
+	defer fd.Unlock()
+	if fd.size != nil {
+		return *fd.size
+	}
+	var st fs.FileInfo
+	var err error
+	if fd.FileOpener != nil {
+		st, err = fs.Stat(fd.FileOpener, fd.Filename)
+	} else {
+		st, err = os.Stat(fd.Filename)
+	}
+	if err != nil {
+		return -1
+	}
+	s := st.Size()
+	fd.size = &s
+	return *fd.size
+}
+
+// Open opens the file itself, bypassing the cache
+func (fd *File) Open() (io.ReadSeekCloser, error) {
+	if fd.FileOpener != nil {
+		f, err := fd.FileOpener.Open(fd.Filename)
+		if err != nil {
+			return nil, err
+		}
+		rsc, ok := f.(io.ReadSeekCloser)
+		if !ok {
+			_ = f.Close()
+			return nil, fmt.Errorf("file isn't readable, seakable or closable")
+		}
+		return rsc, nil
+	}
+	return os.Open(fd.Filename)
+}
+
+// readHead reads the first ReadSize bytes and the size of the file, once
+func (fd *File) readHead() ([]byte, int64, error) {
+	fd.headOnce.Do(func() {
+		f, err := fd.Open()
+		if err != nil {
+			fd.headErr = fmt.Errorf("opening %s: %w", fd.Filename, err)
+			return
+		}
+		defer func() {
+			if err := f.Close(); err != nil {
+				log.Printf("Error: closing %s: %s", fd.Filename, err)
+			}
+		}()
+		b := make([]byte, ReadSize)
+		n, err := io.ReadFull(f, b)
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			fd.headErr = fmt.Errorf("read %d (of %d) from %s: %w", n, len(b), fd.Filename, err)
+			return
+		}
+		fd.head = b[:n]
+		fd.headSize = int64(n)
+		if n == len(b) {
+			if fd.headSize, err = f.Seek(0, io.SeekEnd); err != nil {
+				fd.headErr = fmt.Errorf("seek end of %s: %w", fd.Filename, err)
+			}
+		}
+	})
+	return fd.head, fd.headSize, fd.headErr
+}
+
+// IsBinary ...
+func (fd *File) IsBinary() bool {
+	fd.binaryOnce.Do(func() {
+		head, _, err := fd.readHead()
+		if err != nil || len(head) == 0 {
+			return // So something else can generate the error TODO
+		}
+		detectedMIME := mimetype.Detect(head[:min(len(head), int(1*units.KiB))])
+		fd.binary = true
+		for mtype := detectedMIME; mtype != nil; mtype = mtype.Parent() {
+			if mtype.Is("text/plain") {
+				fd.binary = false
+			}
+		}
+	})
+	return fd.binary
+}
+
+// Survey reads the file, once, working out its charset, whether it ends in a new line and its LineSurvey
+func (fd *File) Survey() (*FileSurvey, error) {
+	fd.surveyOnce.Do(func() {
+		fd.survey, fd.surveyErr = fd.read()
+		// IsBinary needs the head too, the rest of the file has been surveyed so it can go
+		fd.IsBinary()
+		fd.head = nil
+	})
+	return fd.survey, fd.surveyErr
+}
+
+func (fd *File) read() (*FileSurvey, error) {
+	head, size, err := fd.readHead()
+	if err != nil {
+		return nil, err
+	}
+	src := &sampleSource{head: head, size: size, open: fd.Open}
+	defer src.close(fd.Filename)
+	filetype := classify(fd.Filename, head)
+	content := newContentSurveyors(fd.Filename, filetype)
+	surveyors := make([]ContentSurveyor, 0, len(content))
+	for _, cs := range content {
+		surveyors = append(surveyors, cs)
+	}
+	finalNewLine, lines, err := surveyFile(src, fd.Sampling, surveyors...)
+	if err != nil {
+		return nil, fmt.Errorf("surveying %s: %w", fd.Filename, err)
+	}
+	result := &FileSurvey{
+		FinalNewLine: finalNewLine,
+		Lines:        lines,
+		Filetype:     filetype,
+		content:      content,
+	}
+	detector := chardet.NewTextDetector()
+	detected, err := detector.DetectBest(head)
+	if err != nil {
+		if !errors.Is(err, chardet.NotDetectedError) {
+			return nil, fmt.Errorf("detect character encoding from %s: %w", fd.Filename, err)
+		}
+	}
+	if detected != nil && detected.Confidence > 80 {
+		result.Charset = detected.Charset
+		result.CharsetConfidence = detected.Confidence
+	}
+	if bom := SniffBOM(head); bom != "" {
+		// A byte order mark is more certain than the detector
+		result.Charset = bom
+		result.CharsetConfidence = 100
+		result.BOM = true
+	} else if lines.NonASCII == 0 && bytes.IndexByte(head, 0) < 0 {
+		// Pure ASCII fits utf-8 as well as latin1, so it doesn't get a say
+		result.Charset = ""
+		result.CharsetConfidence = 0
+		result.ASCII = true
+	}
+	result.Charset, _ = EditorConfigCharset(result.Charset)
+	return result, nil
+}
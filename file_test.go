@@ -0,0 +1,49 @@
+package ecg_test
+
+import (
+	"io/fs"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	ecg "editorconfig-guesser"
+	_ "editorconfig-guesser/fileformats"
+)
+
+// countingFS counts how many times each file is opened
+type countingFS struct {
+	fs.FS
+	sync.Mutex
+	opens map[string]int
+}
+
+func (c *countingFS) Open(name string) (fs.File, error) {
+	c.Lock()
+	c.opens[name]++
+	c.Unlock()
+	return c.FS.Open(name)
+}
+
+func TestFile_ReadOnce(t *testing.T) {
+	dir := &countingFS{
+		FS: fstest.MapFS{
+			"main.go":  &fstest.MapFile{Data: []byte("package main\n\nfunc main() {\n\tprintln()\n}\n")},
+			"index.ts": &fstest.MapFile{Data: []byte("function start() {\n  return;\n}\n")},
+			"a.sh":     &fstest.MapFile{Data: []byte("#!/bin/sh\necho hi\n")},
+			"Makefile": &fstest.MapFile{Data: []byte("all:\n\techo hi\n")},
+		},
+		opens: map[string]int{},
+	}
+	_, err := ecg.Analyze(dir, func(f *ecg.File) bool { return f.IsBinary() }, ecg.WithSubdirectories(1, 1))
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if dir.opens["main.go"] == 0 {
+		t.Fatal("main.go was never read")
+	}
+	for name, n := range dir.opens {
+		if name != "." && n != 1 {
+			t.Errorf("%s was opened %d times, want 1", name, n)
+		}
+	}
+}
@@ -15,10 +15,18 @@ var (
 
 type Format struct {
 	allFiles *ecg.BasicSurveyor
+	// What the `[*]` section says, filled in by End
+	rendered *ecg.BasicSurveyor
 }
 
+// BasicSurveyor only has the properties the `[*]` section says, so other formats only leave those out of theirs, see
+// ecg.BasicSurveyor.Delta. Indentation and line lengths are left to them.
 func (l *Format) BasicSurveyor() *ecg.BasicSurveyor {
-	return l.allFiles
+	return l.rendered
+}
+
+func (l *Format) SetThresholds(t ecg.Thresholds) {
+	l.allFiles.SetThresholds(t)
 }
 
 func (l *Format) Init() ([]*ecg.SummaryResult, error) {
@@ -35,11 +43,17 @@ func (l *Format) RunFile(f *ecg.File) ([]*ecg.SummaryResult, error) {
 
 func (l *Format) End() ([]*ecg.SummaryResult, error) {
 	l.allFiles.Summarize()
+	l.rendered.InsertFinalNewline = l.allFiles.InsertFinalNewline
+	l.rendered.Charset = l.allFiles.Charset
+	l.rendered.Charsets = l.allFiles.Charsets
+	l.rendered.TrimTrailingWhitespace = l.allFiles.TrimTrailingWhitespace
+	l.rendered.EndOfLine = l.allFiles.EndOfLine
 	return []*ecg.SummaryResult{
 		{
 			FileGlobs:  []string{"*"},
-			Confidence: 3,
+			Confidence: l.allFiles.OverallConfidence(),
 			Template:   l,
+			Surveyor:   l.allFiles,
 			Path:       "/",
 		},
 	}, nil
@@ -56,8 +70,10 @@ func init() {
 	ecg.Register(func() ecg.FileFormat {
 		return ecg.NewContainer("ALl Files", &Format{
 			allFiles: ecg.NewBasicSurveyor(),
+			rendered: ecg.NewBasicSurveyor(),
 		})
 	})
 }
 
 var _ ecg.BasicSurveyorGetter = (*Format)(nil)
+var _ ecg.ThresholdsSetter = (*Format)(nil)
@@ -3,7 +3,6 @@ package fileformattempalte
 import (
 	"bytes"
 	"editorconfig-guesser"
-	_ "embed"
 	"fmt"
 	"path/filepath"
 	"text/template"
@@ -12,12 +11,10 @@ import (
 // This template is for people who are adding MORE types of inputs.
 // Please just vary one of the other formats if you're simply after
 // enabling a file type without its own specific requirements please
-// look at the 'generic' file format.
+// add it to languages/languages.yaml.
 
 var (
-	//go:embed "ectemplate"
-	ectemplate []byte
-	globs      = []string{"*.ts"}
+	globs = []string{"*.ts"}
 )
 
 type Format struct {
@@ -30,22 +27,27 @@ func (l *Format) SetBasicSurveyor(af *ecg.BasicSurveyor) {
 	l.everyFileSurveyor = af
 }
 
+func (l *Format) SetThresholds(t ecg.Thresholds) {
+	l.surveyor.SetThresholds(t)
+}
+
 func (l *Format) Init() ([]*ecg.SummaryResult, error) {
 	return nil, nil
 }
 
-func (l *Format) RunFile(f *ecg.File) ([]*ecg.SummaryResult, error) {
-	match := false
+// Claims lets the generic format know the file is surveyed here
+func (l *Format) Claims(f *ecg.File) bool {
+	_, fn := filepath.Split(f.Filename)
 	for _, gs := range globs {
-		_, fn := filepath.Split(f.Filename)
-		if m, err := filepath.Match(gs, fn); err != nil {
-			return nil, err
-		} else if m {
-			match = true
-			break
+		if m, _ := filepath.Match(gs, fn); m {
+			return true
 		}
 	}
-	if !match {
+	return false
+}
+
+func (l *Format) RunFile(f *ecg.File) ([]*ecg.SummaryResult, error) {
+	if !l.Claims(f) {
 		return nil, nil
 	}
 	l.matches++
@@ -64,8 +66,9 @@ func (l *Format) End() ([]*ecg.SummaryResult, error) {
 	return []*ecg.SummaryResult{
 		{
 			FileGlobs:  globs,
-			Confidence: 1,
+			Confidence: l.surveyor.OverallConfidence(),
 			Template:   l,
+			Surveyor:   l.surveyor,
 			Path:       "/",
 		},
 	}, nil
@@ -73,48 +76,8 @@ func (l *Format) End() ([]*ecg.SummaryResult, error) {
 
 func (l *Format) String() (string, error) {
 	b := bytes.NewBuffer(nil)
-	t := template.Must(template.New("").Parse(string(ectemplate)))
-	var allFiles *ecg.BasicSurveyor
-	if l.everyFileSurveyor == nil {
-		allFiles = l.surveyor
-	} else {
-		allFiles = ecg.NewBasicSurveyor()
-		if l.everyFileSurveyor.InsertFinalNewline != l.surveyor.InsertFinalNewline {
-			allFiles.InsertFinalNewline = l.surveyor.InsertFinalNewline
-		}
-		if l.everyFileSurveyor.Charset != l.surveyor.Charset {
-			allFiles.Charset = l.surveyor.Charset
-		}
-		if l.everyFileSurveyor.Charsets != l.surveyor.Charsets {
-			allFiles.Charsets = l.surveyor.Charsets
-		}
-		if l.everyFileSurveyor.TrimTrailingWhitespace != l.surveyor.TrimTrailingWhitespace {
-			allFiles.TrimTrailingWhitespace = l.surveyor.TrimTrailingWhitespace
-		}
-		if l.everyFileSurveyor.EndOfLine != l.surveyor.EndOfLine {
-			allFiles.EndOfLine = l.surveyor.EndOfLine
-		}
-		if l.everyFileSurveyor.Files != l.surveyor.Files {
-			allFiles.Files = l.surveyor.Files
-		}
-		if l.everyFileSurveyor.CharacterSets != l.surveyor.CharacterSets {
-			allFiles.CharacterSets = l.surveyor.CharacterSets
-		}
-
-		if l.everyFileSurveyor.IndentStyle != l.surveyor.IndentStyle {
-			allFiles.IndentStyle = l.surveyor.IndentStyle
-		}
-		if l.everyFileSurveyor.IndentSize != l.surveyor.IndentSize {
-			allFiles.IndentSize = l.surveyor.IndentSize
-		}
-		if l.everyFileSurveyor.MaxLineLength != l.surveyor.MaxLineLength {
-			allFiles.MaxLineLength = l.surveyor.MaxLineLength
-		}
-		if l.everyFileSurveyor.TabWidth != l.surveyor.TabWidth {
-			allFiles.TabWidth = l.surveyor.TabWidth
-		}
-	}
-	err := t.Execute(b, allFiles)
+	t := template.Must(template.New("").Parse(string(ecg.SurveyedTemplate)))
+	err := t.Execute(b, l.surveyor.Delta(l.everyFileSurveyor))
 	return b.String(), err
 }
 
@@ -127,3 +90,5 @@ func init() {
 }
 
 var _ ecg.BasicSurveyorSetter = (*Format)(nil)
+var _ ecg.FileClaimer = (*Format)(nil)
+var _ ecg.ThresholdsSetter = (*Format)(nil)
@@ -1,27 +1,21 @@
-// Package generic provides generic format.
+// Package generic provides generic format, it surveys the file extensions no other format covers.
 package generic
 
 import (
 	"bytes"
 	"editorconfig-guesser"
-	_ "embed"
 	"fmt"
-	"path/filepath"
-	"strings"
+	"path"
+	"sort"
 	"text/template"
 )
 
-var (
-	//go:embed "ectemplate"
-	ectemplate []byte
-	globs = [][]string{}
-)
-
-// Format ...
+// Format surveys the files of each extension no other format claims, see ecg.FileClaimer
 type Format struct {
 	surveyor          map[string]*ecg.BasicSurveyor
 	everyFileSurveyor *ecg.BasicSurveyor
-	matches           int
+	formats           []ecg.FileFormat
+	thresholds        ecg.Thresholds
 }
 
 // SetBasicSurveyor ...
@@ -29,37 +23,56 @@ func (l *Format) SetBasicSurveyor(af *ecg.BasicSurveyor) {
 	l.everyFileSurveyor = af
 }
 
+// SetThresholds ...
+func (l *Format) SetThresholds(t ecg.Thresholds) {
+	l.thresholds = t
+}
+
+// SetFileFormats ...
+func (l *Format) SetFileFormats(ffs []ecg.FileFormat) {
+	l.formats = ffs
+}
+
 // Init ...
 func (l *Format) Init() ([]*ecg.SummaryResult, error) {
 	return nil, nil
 }
 
-// RunFile ...
-func (l *Format) RunFile(f *ecg.File) ([]*ecg.SummaryResult, error) {
-	var match []string
-	for _, gs := range globs {
-		_, fn := filepath.Split(f.Filename)
-		for _, gss := range gs {
-			if m, err := filepath.Match(gss, fn); err != nil {
-				return nil, err
-			} else if m {
-				match = gs
-				break
-			}
+// extension the file's extension, "" for files without one and hidden files such as .gitignore
+func extension(filename string) string {
+	base := path.Base(filename)
+	ext := path.Ext(base)
+	if ext == base {
+		return ""
+	}
+	return ext
+}
+
+// claimed true if another format surveys the file
+func (l *Format) claimed(f *ecg.File) bool {
+	for _, ff := range l.formats {
+		c, ok := ff.(*ecg.Container)
+		if !ok {
+			continue
 		}
-		if len(match) > 0 {
-			break
+		if fc, ok := c.FileRunner.(ecg.FileClaimer); ok && fc.Claims(f) {
+			return true
 		}
 	}
-	if len(match) == 0 {
+	return false
+}
+
+// RunFile ...
+func (l *Format) RunFile(f *ecg.File) ([]*ecg.SummaryResult, error) {
+	ext := extension(f.Filename)
+	if ext == "" || l.claimed(f) {
 		return nil, nil
 	}
-	l.matches++
-	globstr := strings.Join(match, ":")
-	surveyor, ok := l.surveyor[globstr]
+	surveyor, ok := l.surveyor[ext]
 	if !ok {
 		surveyor = ecg.NewBasicSurveyor()
-		l.surveyor[globstr] = surveyor
+		surveyor.SetThresholds(l.thresholds)
+		l.surveyor[ext] = surveyor
 	}
 	_, _, _, err := surveyor.ReadFile(f)
 	if err != nil {
@@ -68,26 +81,29 @@ func (l *Format) RunFile(f *ecg.File) ([]*ecg.SummaryResult, error) {
 	return nil, nil
 }
 
-// End ...
+// End sections for the extensions with enough files, Consolidate leaves out those which only repeat `[*]`
 func (l *Format) End() ([]*ecg.SummaryResult, error) {
-	if l.matches == 0 {
-		return nil, nil
-	}
-	results := make([]*ecg.SummaryResult, 0, len(globs))
-	for _, gs := range globs {
-		surveyor, ok := l.surveyor[strings.Join(gs, ":")]
-		if !ok {
-			continue
+	exts := make([]string, 0, len(l.surveyor))
+	for ext, surveyor := range l.surveyor {
+		// A handful of files is too few to tell the extension's conventions from chance
+		if surveyor.Files >= l.thresholds.ExtensionMinFiles {
+			exts = append(exts, ext)
 		}
+	}
+	sort.Strings(exts)
+	results := make([]*ecg.SummaryResult, 0, len(exts))
+	for _, ext := range exts {
+		surveyor := l.surveyor[ext]
 		surveyor.Summarize()
 		results = append(results, &ecg.SummaryResult{
-			FileGlobs:  gs,
-			Confidence: 1,
+			FileGlobs:  []string{"*" + ecg.EscapeGlob(ext)},
+			Confidence: surveyor.OverallConfidence(),
 			Template: &Surveyor{
 				everyFileSurveyor: l.everyFileSurveyor,
 				surveyor:          surveyor,
 			},
-			Path: "/",
+			Path:     "/",
+			Surveyor: surveyor,
 		})
 	}
 	return results, nil
@@ -102,57 +118,20 @@ type Surveyor struct {
 // String ...
 func (l *Surveyor) String() (string, error) {
 	b := bytes.NewBuffer(nil)
-	t := template.Must(template.New("").Parse(string(ectemplate)))
-	var allFiles *ecg.BasicSurveyor
-	if l.everyFileSurveyor == nil {
-		allFiles = l.surveyor
-	} else {
-		allFiles = ecg.NewBasicSurveyor()
-		if l.everyFileSurveyor.InsertFinalNewline != l.surveyor.InsertFinalNewline {
-			allFiles.InsertFinalNewline = l.surveyor.InsertFinalNewline
-		}
-		if l.everyFileSurveyor.Charset != l.surveyor.Charset {
-			allFiles.Charset = l.surveyor.Charset
-		}
-		if l.everyFileSurveyor.Charsets != l.surveyor.Charsets {
-			allFiles.Charsets = l.surveyor.Charsets
-		}
-		if l.everyFileSurveyor.TrimTrailingWhitespace != l.surveyor.TrimTrailingWhitespace {
-			allFiles.TrimTrailingWhitespace = l.surveyor.TrimTrailingWhitespace
-		}
-		if l.everyFileSurveyor.EndOfLine != l.surveyor.EndOfLine {
-			allFiles.EndOfLine = l.surveyor.EndOfLine
-		}
-		if l.everyFileSurveyor.Files != l.surveyor.Files {
-			allFiles.Files = l.surveyor.Files
-		}
-		if l.everyFileSurveyor.CharacterSets != l.surveyor.CharacterSets {
-			allFiles.CharacterSets = l.surveyor.CharacterSets
-		}
-
-		if l.everyFileSurveyor.IndentStyle != l.surveyor.IndentStyle {
-			allFiles.IndentStyle = l.surveyor.IndentStyle
-		}
-		if l.everyFileSurveyor.IndentSize != l.surveyor.IndentSize {
-			allFiles.IndentSize = l.surveyor.IndentSize
-		}
-		if l.everyFileSurveyor.MaxLineLength != l.surveyor.MaxLineLength {
-			allFiles.MaxLineLength = l.surveyor.MaxLineLength
-		}
-		if l.everyFileSurveyor.TabWidth != l.surveyor.TabWidth {
-			allFiles.TabWidth = l.surveyor.TabWidth
-		}
-	}
-	err := t.Execute(b, allFiles)
+	t := template.Must(template.New("").Parse(string(ecg.SurveyedTemplate)))
+	err := t.Execute(b, l.surveyor.Delta(l.everyFileSurveyor))
 	return b.String(), err
 }
 
 func init() {
 	ecg.Register(func() ecg.FileFormat {
 		return ecg.NewContainer("Generic", &Format{
-			surveyor: map[string]*ecg.BasicSurveyor{},
+			surveyor:   map[string]*ecg.BasicSurveyor{},
+			thresholds: ecg.DefaultThresholds(),
 		})
 	})
 }
 
 var _ ecg.BasicSurveyorSetter = (*Format)(nil)
+var _ ecg.FileFormatsSetter = (*Format)(nil)
+var _ ecg.ThresholdsSetter = (*Format)(nil)
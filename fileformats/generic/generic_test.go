@@ -0,0 +1,54 @@
+package generic_test
+
+import (
+	ecg "editorconfig-guesser"
+	_ "editorconfig-guesser/fileformats"
+	"testing"
+	"testing/fstest"
+)
+
+func TestGeneric(t *testing.T) {
+	tf := &fstest.MapFile{Data: []byte("resource \"x\" {\n    a = 1\n    b = 2\n}\n")}
+	dir := fstest.MapFS{
+		"a.txt":      &fstest.MapFile{Data: []byte("one\n")},
+		"b.txt":      &fstest.MapFile{Data: []byte("two\n")},
+		"main.tf":    tf,
+		"vars.tf":    tf,
+		"outputs.tf": tf,
+		"api.proto":  &fstest.MapFile{Data: []byte("syntax = \"proto3\";\n\tmessage A {}\n")},
+		"main.go":    &fstest.MapFile{Data: []byte("package main\n\nfunc main() {\n    x()\n}\n")},
+		"main2.go":   &fstest.MapFile{Data: []byte("package main\n\nfunc y() {\n    x()\n}\n")},
+		"main3.go":   &fstest.MapFile{Data: []byte("package main\n\nfunc z() {\n    x()\n}\n")},
+	}
+	analyze := func(opts ...ecg.Option) map[string]*ecg.Section {
+		guess, err := ecg.Analyze(dir, func(f *ecg.File) bool { return false }, opts...)
+		if err != nil {
+			t.Fatalf("Analyze failed: %v", err)
+		}
+		sections := map[string]*ecg.Section{}
+		for _, s := range guess.Sections {
+			if s.Format == "Generic" {
+				sections[s.Header()] = s
+			}
+		}
+		return sections
+	}
+	sections := analyze()
+	tfSection := sections["*.tf"]
+	if tfSection == nil {
+		t.Fatalf("no [*.tf] section, got %v", sections)
+	}
+	if p := tfSection.Get("indent_style"); p == nil || p.Value != "spaces" {
+		t.Errorf("[*.tf] indent_style = %v, want spaces", p)
+	}
+	for _, header := range []string{"*.proto", "*.go", "*.txt"} {
+		if sections[header] != nil {
+			t.Errorf("Generic has a [%s] section", header)
+		}
+	}
+	th := ecg.DefaultThresholds()
+	th.ExtensionMinFiles = 1
+	if sections := analyze(ecg.WithThresholds(th)); sections["*.proto"] == nil {
+		t.Errorf("no [*.proto] section with extension_min_files 1, got %v", sections)
+	}
+}
@@ -1,25 +1,255 @@
-// Package gnumake provides gnumake format.
+// Package gnumake provides gnumake format, recipes have to be indented with tabs whatever the rest of the makefile
+// uses.
 package gnumake
 
 import (
-	"editorconfig-guesser"
+	"bytes"
+	ecg "editorconfig-guesser"
 	_ "embed"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"text/template"
 )
 
 var (
 	//go:embed "ectemplate"
 	ectemplate []byte
+	globs      = []string{
+		"Makefile",
+		"GNUmakefile",
+		"makefile",
+		"*.mk",
+		"*.mak",
+	}
+	// Makefiles without one of the names, ie with a `# vim: ft=make` modeline, see ecg.File.Filetype
+	filetypes = []string{"make"}
 )
 
+// contentName what the makefileSurveyor is registered as, see ecg.RegisterContentSurveyor
+const contentName = "GNU Make"
+
+// Format ...
+type Format struct {
+	surveyor          *ecg.BasicSurveyor
+	everyFileSurveyor *ecg.BasicSurveyor
+	matches           int
+	// The globs which matched a file and the makefiles matched by their filetype
+	matched map[string]bool
+	paths   []string
+	// The indented lines which aren't recipes
+	other *ecg.LineSurveyor
+	// The recipe lines indented with spaces of each file
+	spaceRecipes map[string][]int
+	// The makefiles which set .RECIPEPREFIX, their recipes needn't start with a tab
+	recipePrefixes []string
+	thresholds     ecg.Thresholds
+}
+
+// SetBasicSurveyor ...
+func (l *Format) SetBasicSurveyor(af *ecg.BasicSurveyor) {
+	l.everyFileSurveyor = af
+}
+
+// SetThresholds ...
+func (l *Format) SetThresholds(t ecg.Thresholds) {
+	l.thresholds = t
+	l.surveyor.SetThresholds(t)
+}
+
+// Init ...
+func (l *Format) Init() ([]*ecg.SummaryResult, error) {
+	return nil, nil
+}
+
+// globFor the glob the file's name matches, "" if none do
+func globFor(filename string) string {
+	_, fn := filepath.Split(filename)
+	for _, g := range globs {
+		if m, _ := filepath.Match(g, fn); m {
+			return g
+		}
+	}
+	return ""
+}
+
+// Claims ...
+func (l *Format) Claims(f *ecg.File) bool {
+	return globFor(f.Filename) != "" || f.HasFiletype(filetypes)
+}
+
+// wants the files the makefileSurveyor reads, the same as Claims
+func wants(filename, filetype string) bool {
+	return globFor(filename) != "" || slices.Contains(filetypes, filetype)
+}
+
+// RunFile ...
+func (l *Format) RunFile(f *ecg.File) ([]*ecg.SummaryResult, error) {
+	if glob := globFor(f.Filename); glob != "" {
+		l.matched[glob] = true
+	} else if f.HasFiletype(filetypes) {
+		l.paths = append(l.paths, ecg.ExactGlob(f.Filename))
+	} else {
+		return nil, nil
+	}
+	l.matches++
+	_, _, _, err := l.surveyor.ReadFile(f)
+	if err != nil {
+		return nil, fmt.Errorf("running: %w", err)
+	}
+	// The recipes were sorted out as the file was read for the survey
+	read, err := f.Survey()
+	if err != nil {
+		return nil, fmt.Errorf("running: %w", err)
+	}
+	s, ok := read.Content(contentName).(*makefileSurveyor)
+	if !ok {
+		return nil, fmt.Errorf("running: %s wasn't read as a makefile", f.Filename)
+	}
+	if s.customPrefix {
+		l.recipePrefixes = append(l.recipePrefixes, f.Filename)
+	}
+	for _, line := range s.other {
+		_, _ = l.other.Write([]byte(line))
+	}
+	if len(s.spaceRecipes) > 0 {
+		l.spaceRecipes[f.Filename] = s.spaceRecipes
+	}
+	return nil, nil
+}
+
+// End ...
+func (l *Format) End() ([]*ecg.SummaryResult, error) {
+	if l.matches == 0 {
+		return nil, nil
+	}
+	// Files arrive in whatever order they were read in
+	sort.Strings(l.paths)
+	filenames := make([]string, 0, len(l.spaceRecipes))
+	for filename := range l.spaceRecipes {
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+	var warnings []*ecg.Warning
+	for _, filename := range filenames {
+		for _, n := range l.spaceRecipes[filename] {
+			// n is 0 past a part of the file which wasn't sampled
+			warnings = append(warnings, &ecg.Warning{
+				Filename: filename,
+				Line:     n,
+				Message:  `recipe is indented with spaces, make fails with "missing separator"`,
+			})
+		}
+	}
+	sort.Strings(l.recipePrefixes)
+	// Unless every makefile picks its own prefix
+	tabs := len(l.recipePrefixes) < l.matches
+	if tabs {
+		for _, filename := range l.recipePrefixes {
+			warnings = append(warnings, &ecg.Warning{
+				Filename: filename,
+				Message:  "sets .RECIPEPREFIX but the other makefiles need recipes to start with a tab",
+			})
+		}
+	}
+	l.surveyor.Summarize()
+	if tabs {
+		// Make decides the indentation of recipes, not the survey
+		l.surveyor.Confidence["indent_style"] = 1
+	}
+	fileGlobs := make([]string, 0, len(globs)+len(l.paths))
+	for _, g := range globs {
+		if l.matched[g] {
+			fileGlobs = append(fileGlobs, g)
+		}
+	}
+	return []*ecg.SummaryResult{
+		{
+			FileGlobs:  append(fileGlobs, l.paths...),
+			Confidence: l.surveyor.OverallConfidence(),
+			Template: &Surveyor{
+				everyFileSurveyor: l.everyFileSurveyor,
+				surveyor:          l.surveyor,
+				otherIndentation:  otherIndentation(l.other.Survey(), l.thresholds.IndentStyle),
+				tabs:              tabs,
+			},
+			Path:     "/",
+			Surveyor: l.surveyor,
+			Warnings: warnings,
+		},
+	}, nil
+}
+
+// otherIndentation how the lines which aren't recipes are indented, ie "2 spaces" or "tabs", when share of them agree.
+// "" if there are none.
+func otherIndentation(ls *ecg.LineSurvey, share float64) string {
+	tabs := ls.IndentedWith('\t')
+	spaces := ls.IndentedWith(' ')
+	total := tabs + spaces
+	switch {
+	case total == 0:
+		return ""
+	case float64(tabs)/float64(total) >= share:
+		return "tabs"
+	case float64(spaces)/float64(total) < share:
+		return "a mix of tabs and spaces"
+	}
+	for _, size := range []int{8, 4, 3, 2} {
+		multiples := 0
+		for prefix, n := range ls.WhitespacePrefix {
+			if strings.Trim(prefix, " ") == "" && len(prefix)%size == 0 {
+				multiples += n
+			}
+		}
+		if float64(multiples)/float64(spaces) >= share {
+			return fmt.Sprintf("%d spaces", size)
+		}
+	}
+	return "spaces"
+}
+
+// Surveyor ...
+type Surveyor struct {
+	everyFileSurveyor *ecg.BasicSurveyor
+	surveyor          *ecg.BasicSurveyor
+	otherIndentation  string
+	// Recipes have to start with a tab, there are makefiles which don't set .RECIPEPREFIX
+	tabs bool
+}
+
+// String ...
+func (l *Surveyor) String() (string, error) {
+	b := bytes.NewBuffer(nil)
+	t := template.Must(template.New("").Parse(string(ectemplate)))
+	err := t.Execute(b, struct {
+		*ecg.BasicSurveyor
+		OtherIndentation string
+		Tabs             bool
+	}{
+		BasicSurveyor:    l.surveyor.Delta(l.everyFileSurveyor),
+		OtherIndentation: l.otherIndentation,
+		Tabs:             l.tabs,
+	})
+	return b.String(), err
+}
+
 func init() {
+	ecg.RegisterContentSurveyor(contentName, wants, func() ecg.ContentSurveyor {
+		return &makefileSurveyor{}
+	})
 	ecg.Register(func() ecg.FileFormat {
-		return ecg.NewPresence(
-			"GNU Make",
-			[]string{
-				"Makefile",
-				"*.mk",
-			},
-			ectemplate,
-		)
+		return ecg.NewContainer("GNU Make", &Format{
+			surveyor:     ecg.NewBasicSurveyor(),
+			matched:      map[string]bool{},
+			other:        ecg.NewLineSurveyor(),
+			spaceRecipes: map[string][]int{},
+			thresholds:   ecg.DefaultThresholds(),
+		})
 	})
 }
+
+var _ ecg.BasicSurveyorSetter = (*Format)(nil)
+var _ ecg.FileClaimer = (*Format)(nil)
+var _ ecg.ThresholdsSetter = (*Format)(nil)
@@ -0,0 +1,94 @@
+package gnumake_test
+
+import (
+	ecg "editorconfig-guesser"
+	_ "editorconfig-guesser/fileformats"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestGNUMake(t *testing.T) {
+	makefile := "CC ?= gcc\n" +
+		"SRCS = a.c \\\n  b.c \\\n  c.c\n" +
+		"\n" +
+		"all: build\n\techo hi\n" +
+		"ifeq ($(X),1)\n  FOO = 1\nelse\n  FOO = 2\nendif\n" +
+		"\n" +
+		"build: $(SRCS:.c=.o)\n\t$(CC) -o $@ $^ \\\n\t    -lm\n"
+	dir := fstest.MapFS{
+		"Makefile":      &fstest.MapFile{Data: []byte(makefile)},
+		"sub/rules.mak": &fstest.MapFile{Data: []byte("X := 1\n\ntest:\n    go test ./...\n\tok\n")},
+		"scripts/build": &fstest.MapFile{Data: []byte("# vim: ft=make\nall:\n\ttrue\n")},
+	}
+	guess, err := ecg.Analyze(dir, func(f *ecg.File) bool { return false })
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	var section *ecg.Section
+	for _, s := range guess.Sections {
+		if s.Format == "GNU Make" {
+			section = s
+		}
+	}
+	if section == nil {
+		t.Fatalf("no GNU Make section in:\n%s", guess)
+	}
+	if got, want := section.Header(), "{Makefile,*.mak,/scripts/build}"; got != want {
+		t.Errorf("Header() = %q, want %q", got, want)
+	}
+	p := section.Get("indent_style")
+	if p == nil || p.Value != "tab" || p.Confidence != 1 {
+		t.Fatalf("indent_style = %v, want tab with confidence 1", p)
+	}
+	if p.Comment != "" {
+		t.Errorf("indent_style comment = %q, want none", p.Comment)
+	}
+	var warnings []string
+	for _, w := range guess.Warnings {
+		warnings = append(warnings, w.String())
+	}
+	want := `sub/rules.mak:4: GNU Make: recipe is indented with spaces, make fails with "missing separator"`
+	if got := strings.Join(warnings, "\n"); got != want {
+		t.Errorf("Warnings = %q, want %q", got, want)
+	}
+	if !strings.Contains(section.String(), "indented with 2 spaces") {
+		t.Errorf("section doesn't say other lines are indented with 2 spaces:\n%s", section)
+	}
+}
+
+func TestGNUMake_RecipePrefix(t *testing.T) {
+	prefixed := &fstest.MapFile{Data: []byte(".RECIPEPREFIX = >\nall:\n>echo hi\n")}
+	analyze := func(dir fstest.MapFS) (*ecg.Section, []*ecg.Warning) {
+		guess, err := ecg.Analyze(dir, func(f *ecg.File) bool { return false })
+		if err != nil {
+			t.Fatalf("Analyze failed: %v", err)
+		}
+		for _, s := range guess.Sections {
+			if s.Format == "GNU Make" {
+				return s, guess.Warnings
+			}
+		}
+		t.Fatalf("no GNU Make section in:\n%s", guess)
+		return nil, nil
+	}
+	s, warnings := analyze(fstest.MapFS{"Makefile": prefixed, "sub/Makefile": prefixed})
+	if strings.Contains(s.String(), "Makefiles require tabs") {
+		t.Errorf("section says makefiles require tabs when .RECIPEPREFIX is set:\n%s", s)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("Warnings = %v, want none when every makefile sets .RECIPEPREFIX", warnings)
+	}
+	if p := s.Get("indent_style"); p != nil && p.Confidence == 1 {
+		t.Errorf("indent_style = %v, want it left to the survey", p)
+	}
+	s, warnings = analyze(fstest.MapFS{"Makefile": prefixed, "rules.mk": &fstest.MapFile{Data: []byte("all:\n\techo hi\n")}})
+	p := s.Get("indent_style")
+	if p == nil || p.Value != "tab" || p.Confidence != 1 {
+		t.Fatalf("indent_style = %v, want tab with confidence 1", p)
+	}
+	want := "Makefile: GNU Make: sets .RECIPEPREFIX but the other makefiles need recipes to start with a tab"
+	if len(warnings) != 1 || warnings[0].String() != want {
+		t.Errorf("Warnings = %v, want %q", warnings, want)
+	}
+}
@@ -0,0 +1,218 @@
+package gnumake
+
+import (
+	"bytes"
+	ecg "editorconfig-guesser"
+	"regexp"
+	"strings"
+)
+
+// directives which can start a line outside of a recipe, ifeq and the other conditionals may be indented with spaces
+// in the middle of a rule
+var directives = map[string]bool{
+	"ifeq":     true,
+	"ifneq":    true,
+	"ifdef":    true,
+	"ifndef":   true,
+	"else":     true,
+	"endif":    true,
+	"define":   true,
+	"endef":    true,
+	"include":  true,
+	"-include": true,
+	"sinclude": true,
+	"export":   true,
+	"unexport": true,
+	"override": true,
+	"private":  true,
+	"undefine": true,
+	"vpath":    true,
+}
+
+// conditionals are the directives which don't end a rule's recipe
+var conditionals = map[string]bool{
+	"ifeq":   true,
+	"ifneq":  true,
+	"ifdef":  true,
+	"ifndef": true,
+	"else":   true,
+	"endif":  true,
+}
+
+// assignment a variable assignment such as `CFLAGS += -O2` or `X ::= y`
+var assignment = regexp.MustCompile(`^[^\s:#=]+\s*(?:::?|:::|[+?!])?=`)
+
+// survey what a makefile's indented lines are, recipes have to start with a tab while the rest (conditionals, continued
+// variables, define bodies) can be indented however the project likes
+type survey struct {
+	// Indented lines which aren't part of a recipe, with their new lines
+	other []string
+	// Line numbers of recipe lines indented with spaces, make stops with "missing separator" on them. 0 for those past
+	// a part of the file which wasn't sampled.
+	spaceRecipes []int
+	// The makefile sets .RECIPEPREFIX so recipes needn't start with a tab
+	customPrefix bool
+}
+
+// firstWord the first word of a line after its indentation
+func firstWord(line string) string {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+// continues true if the line ends in an odd number of backslashes, so the next line is part of it
+func continues(line string) bool {
+	n := len(line) - len(strings.TrimRight(line, "\\"))
+	return n%2 == 1
+}
+
+// isDefine true if the line starts a multi-line variable, ie `define X` or `override define X`
+func isDefine(line string) bool {
+	fields := strings.Fields(line)
+	for len(fields) > 1 && (fields[0] == "override" || fields[0] == "export" || fields[0] == "private") {
+		fields = fields[1:]
+	}
+	return len(fields) > 0 && fields[0] == "define"
+}
+
+// isRule true if the unindented line is a rule such as `all: build` rather than an assignment or directive
+func isRule(line string) bool {
+	if directives[firstWord(line)] || assignment.MatchString(line) {
+		return false
+	}
+	depth := 0
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '(', '{':
+			depth++
+		case ')', '}':
+			depth--
+		case '#':
+			return false
+		case '=':
+			if depth == 0 {
+				return false
+			}
+		case ':':
+			if depth > 0 {
+				continue
+			}
+			rest := strings.TrimLeft(line[i:], ":")
+			return !strings.HasPrefix(rest, "=")
+		}
+	}
+	return false
+}
+
+// makefileSurveyor sorts the indented lines of a makefile into recipes and the rest as the file is read, see
+// ecg.ContentSurveyor
+type makefileSurveyor struct {
+	survey
+	// The line written so far
+	partial []byte
+	// The line number, which is lost once a part of the file is skipped
+	n       int
+	skipped bool
+	inRule  bool
+	// The previous line ended in a backslash, and whether it was part of a recipe
+	continued, recipe bool
+	defines           int
+}
+
+// Write ...
+func (m *makefileSurveyor) Write(b []byte) (int, error) {
+	n := len(b)
+	for len(b) > 0 {
+		nl := bytes.IndexByte(b, '\n')
+		if nl < 0 {
+			m.partial = append(m.partial, b...)
+			break
+		}
+		m.line(string(append(m.partial, b[:nl+1]...)))
+		m.partial = m.partial[:0]
+		b = b[nl+1:]
+	}
+	return n, nil
+}
+
+// Break the lines which follow are in the middle of the makefile, what they belong to is lost
+func (m *makefileSurveyor) Break() {
+	m.partial = m.partial[:0]
+	m.skipped = true
+	m.inRule, m.continued, m.recipe = false, false, false
+	m.defines = 0
+}
+
+// End surveys the last line when the makefile doesn't end in a new line
+func (m *makefileSurveyor) End() {
+	if len(m.partial) > 0 {
+		m.line(string(m.partial))
+		m.partial = nil
+	}
+}
+
+// line sorts one line of the makefile, with its new line
+func (m *makefileSurveyor) line(line string) {
+	m.n++
+	text := strings.TrimRight(line, "\r\n")
+	trimmed := strings.TrimLeft(text, " \t")
+	indented := trimmed != "" && len(trimmed) < len(text)
+	word := firstWord(trimmed)
+	isRecipe := false
+	switch {
+	case m.continued:
+		if indented && !m.recipe {
+			m.other = append(m.other, line)
+		}
+	case m.defines > 0:
+		// The body of a multi-line variable is taken as it is
+		if word == "endef" {
+			m.defines--
+		} else if isDefine(trimmed) {
+			m.defines++
+		} else if indented {
+			m.other = append(m.other, line)
+		}
+	case trimmed == "" || strings.HasPrefix(trimmed, "#"):
+		// Blank lines and comments don't end a recipe
+	case m.customPrefix:
+		if indented {
+			m.other = append(m.other, line)
+		}
+	case strings.HasPrefix(text, "\t") && m.inRule:
+		// A recipe line, whatever it says is up to the shell
+		isRecipe = true
+	case indented:
+		// Indented with spaces, or a tab outside of a rule where make takes it as any other line
+		if m.inRule && text[0] == ' ' && !directives[word] && !assignment.MatchString(trimmed) {
+			n := m.n
+			if m.skipped {
+				n = 0
+			}
+			m.spaceRecipes = append(m.spaceRecipes, n)
+			isRecipe = true
+		} else {
+			m.other = append(m.other, line)
+		}
+	default:
+		switch {
+		case conditionals[word]:
+		case isDefine(trimmed):
+			m.defines++
+			m.inRule = false
+		case strings.HasPrefix(word, ".RECIPEPREFIX"):
+			m.customPrefix = true
+		default:
+			m.inRule = isRule(text)
+		}
+	}
+	if !m.continued {
+		m.recipe = isRecipe
+	}
+	m.continued = continues(text)
+}
+
+var _ ecg.ContentSurveyor = (*makefileSurveyor)(nil)
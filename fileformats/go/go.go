@@ -1,25 +1,25 @@
-package gnumake
+// Package golang provides go format, gofmt decides the indentation and the files the rest.
+package golang
 
 import (
 	"editorconfig-guesser"
-	_ "embed"
-)
-
-var (
-	//go:embed "ectemplate"
-	ectemplate []byte
 )
 
 func init() {
 	ecg.Register(func() ecg.FileFormat {
-		return ecg.NewPresence(
+		return ecg.NewMandatedPresence(
 			"Go",
 			[]string{
 				"go.mod",
 				"go.sum",
 				"*.go",
 			},
-			ectemplate,
+			nil,
+			[]ecg.Mandate{
+				{Key: "indent_style", Value: "tab", Reason: "gofmt indents with tabs"},
+				{Key: "indent_size", Value: "4"},
+			},
+			nil,
 		)
 	})
 }
@@ -2,25 +2,10 @@ package fileformats
 
 import (
 	_ "editorconfig-guesser/fileformats/allfiles"
-	_ "editorconfig-guesser/fileformats/cpp"
-	_ "editorconfig-guesser/fileformats/csharp"
-	_ "editorconfig-guesser/fileformats/css"
 	_ "editorconfig-guesser/fileformats/generic"
 	_ "editorconfig-guesser/fileformats/gnumake"
 	_ "editorconfig-guesser/fileformats/go"
-	_ "editorconfig-guesser/fileformats/html"
-	_ "editorconfig-guesser/fileformats/java"
-	_ "editorconfig-guesser/fileformats/json"
-	_ "editorconfig-guesser/fileformats/markdown"
-	_ "editorconfig-guesser/fileformats/php"
+	_ "editorconfig-guesser/fileformats/languages"
 	_ "editorconfig-guesser/fileformats/python"
-	_ "editorconfig-guesser/fileformats/kotlin"
-	_ "editorconfig-guesser/fileformats/ruby"
-	_ "editorconfig-guesser/fileformats/rust"
 	_ "editorconfig-guesser/fileformats/shell"
-	_ "editorconfig-guesser/fileformats/svelte"
-	_ "editorconfig-guesser/fileformats/swift"
-	_ "editorconfig-guesser/fileformats/typescript"
-	_ "editorconfig-guesser/fileformats/xml"
-	_ "editorconfig-guesser/fileformats/yaml"
 )
@@ -0,0 +1,279 @@
+// Package languages provides the formats which only differ in their name and globs, declared in languages.yaml.
+package languages
+
+import (
+	"bytes"
+	ecg "editorconfig-guesser"
+	"embed"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	//go:embed "languages.yaml"
+	table []byte
+	//go:embed "templates"
+	templates embed.FS
+)
+
+// Language an entry of languages.yaml
+type Language struct {
+	// Name of the format, shown in reports
+	Name string `yaml:"name"`
+	// File name patterns, ie `*.rb`
+	Globs []string `yaml:"globs"`
+	// Exact file names, ie `Gemfile`
+	Filenames []string `yaml:"filenames"`
+	// Filetypes of the files without an extension to survey too, ie `ruby`, see ecg.File.Filetype
+	Filetypes []string `yaml:"filetypes"`
+	// A file in templates/ without the .ectemplate extension, ecg.SurveyedTemplate if empty
+	Template string `yaml:"template"`
+	// Properties to use when the survey can't decide
+	Defaults map[string]string `yaml:"defaults"`
+	// Another Language whose section this one only adds to
+	Parent string `yaml:"parent"`
+
+	template *template.Template
+}
+
+// Parse reads a table of languages in the languages.yaml format
+func Parse(b []byte) ([]*Language, error) {
+	var result []*Language
+	if err := yaml.Unmarshal(b, &result); err != nil {
+		return nil, fmt.Errorf("parsing languages: %w", err)
+	}
+	return result, nil
+}
+
+// Register validates the language and registers it as a FileFormat
+func Register(l *Language) error {
+	if l.Name == "" {
+		return fmt.Errorf("language without a name")
+	}
+	if len(l.Globs) == 0 && len(l.Filenames) == 0 && len(l.Filetypes) == 0 {
+		return fmt.Errorf("%s: no globs, filenames or filetypes", l.Name)
+	}
+	for _, g := range l.Globs {
+		if _, err := filepath.Match(g, ""); err != nil {
+			return fmt.Errorf("%s: glob %s: %w", l.Name, g, err)
+		}
+	}
+	for k := range l.Defaults {
+		if _, ok := properties(ecg.NewBasicSurveyor())[k]; !ok {
+			return fmt.Errorf("%s: unknown default %s", l.Name, k)
+		}
+	}
+	name, b := "default", ecg.SurveyedTemplate
+	if l.Template != "" {
+		var err error
+		name = l.Template
+		if b, err = templates.ReadFile("templates/" + name + ".ectemplate"); err != nil {
+			return fmt.Errorf("%s: template %s: %w", l.Name, name, err)
+		}
+	}
+	var err error
+	if l.template, err = template.New(name).Parse(string(b)); err != nil {
+		return fmt.Errorf("%s: template %s: %w", l.Name, name, err)
+	}
+	ecg.Register(func() ecg.FileFormat {
+		return ecg.NewContainer(l.Name, &Format{
+			language:   l,
+			surveyor:   ecg.NewBasicSurveyor(),
+			thresholds: ecg.DefaultThresholds(),
+		})
+	})
+	return nil
+}
+
+// properties the BasicSurveyor field behind each editorconfig property
+func properties(s *ecg.BasicSurveyor) map[string]*string {
+	return map[string]*string{
+		"insert_final_newline":     &s.InsertFinalNewline,
+		"charset":                  &s.Charset,
+		"trim_trailing_whitespace": &s.TrimTrailingWhitespace,
+		"end_of_line":              &s.EndOfLine,
+		"indent_style":             &s.IndentStyle,
+		"indent_size":              &s.IndentSize,
+		"max_line_length":          &s.MaxLineLength,
+		"tab_width":                &s.TabWidth,
+	}
+}
+
+// Format surveys the files matching a Language
+type Format struct {
+	language          *Language
+	surveyor          *ecg.BasicSurveyor
+	everyFileSurveyor *ecg.BasicSurveyor
+	formats           []ecg.FileFormat
+	matches           int
+	// The files matched by their filetype and the surveyor they share
+	paths      []string
+	classified *ecg.BasicSurveyor
+	thresholds ecg.Thresholds
+}
+
+// SetBasicSurveyor ...
+func (l *Format) SetBasicSurveyor(af *ecg.BasicSurveyor) {
+	l.everyFileSurveyor = af
+}
+
+// SetFileFormats ...
+func (l *Format) SetFileFormats(ffs []ecg.FileFormat) {
+	l.formats = ffs
+}
+
+// SetThresholds ...
+func (l *Format) SetThresholds(t ecg.Thresholds) {
+	l.thresholds = t
+	l.surveyor.SetThresholds(t)
+}
+
+// Init ...
+func (l *Format) Init() ([]*ecg.SummaryResult, error) {
+	return nil, nil
+}
+
+// Matches true if the file name matches one of the language's globs or file names
+func (l *Language) Matches(filename string) bool {
+	_, fn := filepath.Split(filename)
+	for _, f := range l.Filenames {
+		if f == fn {
+			return true
+		}
+	}
+	for _, g := range l.Globs {
+		// Globs are checked by Register
+		if m, _ := filepath.Match(g, fn); m {
+			return true
+		}
+	}
+	return false
+}
+
+// Claims ...
+func (l *Format) Claims(f *ecg.File) bool {
+	return l.language.Matches(f.Filename) || f.HasFiletype(l.language.Filetypes)
+}
+
+// RunFile ...
+func (l *Format) RunFile(f *ecg.File) ([]*ecg.SummaryResult, error) {
+	surveyor := l.surveyor
+	switch {
+	case l.language.Matches(f.Filename):
+		l.matches++
+	case f.HasFiletype(l.language.Filetypes):
+		if l.classified == nil {
+			l.classified = ecg.NewBasicSurveyor()
+			l.classified.SetThresholds(l.thresholds)
+		}
+		surveyor = l.classified
+		l.paths = append(l.paths, ecg.ExactGlob(f.Filename))
+	default:
+		return nil, nil
+	}
+	_, _, _, err := surveyor.ReadFile(f)
+	if err != nil {
+		return nil, fmt.Errorf("running: %w", err)
+	}
+	return nil, nil
+}
+
+// End ...
+func (l *Format) End() ([]*ecg.SummaryResult, error) {
+	var results []*ecg.SummaryResult
+	if l.matches > 0 {
+		l.summarize(l.surveyor)
+		globs := append(append([]string{}, l.language.Globs...), l.language.Filenames...)
+		results = append(results, &ecg.SummaryResult{
+			FileGlobs:  globs,
+			Confidence: l.surveyor.OverallConfidence(),
+			Template:   l,
+			Surveyor:   l.surveyor,
+			Path:       "/",
+		})
+	}
+	if l.classified != nil {
+		l.summarize(l.classified)
+		// Files arrive in whatever order they were read in
+		sort.Strings(l.paths)
+		results = append(results, &ecg.SummaryResult{
+			FileGlobs:  l.paths,
+			Confidence: l.classified.OverallConfidence(),
+			Template:   &classified{l},
+			Surveyor:   l.classified,
+			Path:       "/",
+		})
+	}
+	return results, nil
+}
+
+// summarize summarizes the survey and fills in the language's defaults where it couldn't decide
+func (l *Format) summarize(s *ecg.BasicSurveyor) {
+	s.Summarize()
+	fields := properties(s)
+	for k, v := range l.language.Defaults {
+		if f := fields[k]; *f == "" {
+			*f = v
+		}
+	}
+}
+
+// parent the surveyor of the parent language if it matched any files, otherwise the one for every file
+func (l *Format) parent() *ecg.BasicSurveyor {
+	if l.language.Parent == "" {
+		return l.everyFileSurveyor
+	}
+	for _, ff := range l.formats {
+		c, ok := ff.(*ecg.Container)
+		if !ok || c.Name() != l.language.Parent {
+			continue
+		}
+		if p, ok := c.FileRunner.(*Format); ok && p.matches > 0 {
+			return p.surveyor
+		}
+	}
+	return l.everyFileSurveyor
+}
+
+// String ...
+func (l *Format) String() (string, error) {
+	return l.render(l.surveyor)
+}
+
+// render executes the language's template with what the survey found beyond its parent
+func (l *Format) render(s *ecg.BasicSurveyor) (string, error) {
+	b := bytes.NewBuffer(nil)
+	err := l.language.template.Execute(b, s.Delta(l.parent()))
+	return b.String(), err
+}
+
+// classified the section of the files matched by their filetype
+type classified struct {
+	*Format
+}
+
+// String ...
+func (c *classified) String() (string, error) {
+	return c.render(c.Format.classified)
+}
+
+func init() {
+	languages, err := Parse(table)
+	if err != nil {
+		panic(err)
+	}
+	for _, l := range languages {
+		if err := Register(l); err != nil {
+			panic(fmt.Errorf("languages.yaml: %w", err))
+		}
+	}
+}
+
+var _ ecg.BasicSurveyorSetter = (*Format)(nil)
+var _ ecg.FileFormatsSetter = (*Format)(nil)
+var _ ecg.FileClaimer = (*Format)(nil)
+var _ ecg.ThresholdsSetter = (*Format)(nil)
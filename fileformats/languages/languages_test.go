@@ -0,0 +1,85 @@
+package languages
+
+import (
+	ecg "editorconfig-guesser"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestParse(t *testing.T) {
+	languages, err := Parse(table)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	byName := map[string]*Language{}
+	for _, l := range languages {
+		byName[l.Name] = l
+	}
+	for name, cases := range map[string]map[string]bool{
+		"Ruby": {"lib/a.rb": true, "Gemfile": true, "sub/Rakefile": true, "Gemfile.lock": false, "a.py": false},
+		"HTML": {"index.html": true, "old/index.htm": true, "a.xhtml": false},
+		// TOML isn't Rust, Cargo.toml is left to the generic format with the other .toml files
+		"Rust": {"src/main.rs": true, "Cargo.toml": false},
+	} {
+		l := byName[name]
+		if l == nil {
+			t.Errorf("%s is missing from languages.yaml", name)
+			continue
+		}
+		for fn, want := range cases {
+			if got := l.Matches(fn); got != want {
+				t.Errorf("%s Matches(%s) = %v, want %v", name, fn, got, want)
+			}
+		}
+	}
+}
+
+func TestRegister_Invalid(t *testing.T) {
+	for _, l := range []*Language{
+		{Globs: []string{"*.x"}},
+		{Name: "No globs"},
+		{Name: "Bad glob", Globs: []string{"[x"}},
+		{Name: "Bad default", Globs: []string{"*.x"}, Defaults: map[string]string{"indent": "2"}},
+		{Name: "Bad template", Globs: []string{"*.x"}, Template: "missing"},
+	} {
+		if err := Register(l); err == nil {
+			t.Errorf("Register(%+v) succeeded, want an error", l)
+		}
+	}
+}
+
+func TestRegister_ParentAndDefaults(t *testing.T) {
+	for _, l := range []*Language{
+		{Name: "Test Child", Globs: []string{"*.tc"}, Parent: "Test Parent", Defaults: map[string]string{"max_line_length": "100"}},
+		{Name: "Test Parent", Globs: []string{"*.tp"}},
+	} {
+		if err := Register(l); err != nil {
+			t.Fatalf("Register failed: %v", err)
+		}
+	}
+	dir := fstest.MapFS{
+		"a.tp": &fstest.MapFile{Data: []byte("a\n\tb\n")},
+		"b.tc": &fstest.MapFile{Data: []byte("a\n\tb\n")},
+	}
+	guess, err := ecg.Analyze(dir, func(f *ecg.File) bool { return false }, ecg.WithoutConsolidation())
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	parent, child := guess.Section("*.tp"), guess.Section("*.tc")
+	if parent == nil || child == nil {
+		t.Fatalf("Analyze() is missing a section:\n%s", guess)
+	}
+	if parent.Get("indent_size") == nil {
+		t.Errorf("parent section has no indent_size:\n%s", parent)
+	}
+	if child.Get("indent_size") != nil {
+		t.Errorf("child section repeats its parent's indent_size:\n%s", child)
+	}
+	if p := child.Get("max_line_length"); p == nil || p.Value != "100" {
+		t.Errorf("child section didn't take the default max_line_length:\n%s", child)
+	}
+	if strings.Contains(child.String(), "end_of_line") {
+		t.Errorf("child section repeats its parent's end_of_line:\n%s", child)
+	}
+}
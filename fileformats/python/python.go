@@ -1,23 +1,25 @@
+// Package python provides python format, PEP 8 decides the indentation and the files the rest.
 package python
 
 import (
 	ecg "editorconfig-guesser"
-	_ "embed"
-)
-
-var (
-	//go:embed "ectemplate"
-	ectemplate []byte
 )
 
 func init() {
 	ecg.Register(func() ecg.FileFormat {
-		return ecg.NewPresence(
+		return ecg.NewMandatedPresence(
 			"Python",
 			[]string{
 				"*.py",
 			},
-			ectemplate,
+			[]string{
+				"python",
+			},
+			[]ecg.Mandate{
+				{Key: "indent_style", Value: "space", Reason: "PEP 8 indents with 4 spaces"},
+				{Key: "indent_size", Value: "4"},
+			},
+			nil,
 		)
 	})
 }
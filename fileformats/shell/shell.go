@@ -4,17 +4,15 @@ package shell
 import (
 	"bytes"
 	ecg "editorconfig-guesser"
-	_ "embed"
 	"fmt"
 	"path/filepath"
+	"sort"
 	"strings"
 	"text/template"
 )
 
 var (
-	//go:embed "ectemplate"
-	ectemplate []byte
-	globs      = [][]string{
+	globs = [][]string{
 		{"*.sh"},
 		{"*.bash"},
 		{"*.zsh"},
@@ -23,6 +21,8 @@ var (
 		{"*.csh"},
 		{"*.tcsh"},
 	}
+	// Scripts without an extension with these filetypes are surveyed too, see ecg.File.Filetype
+	filetypes = []string{"sh", "zsh", "fish", "csh"}
 )
 
 // Format ...
@@ -30,6 +30,10 @@ type Format struct {
 	surveyor          map[string]*ecg.BasicSurveyor
 	everyFileSurveyor *ecg.BasicSurveyor
 	matches           int
+	// The scripts matched by their filetype and the surveyor they share
+	paths      []string
+	classified *ecg.BasicSurveyor
+	thresholds ecg.Thresholds
 }
 
 // SetBasicSurveyor ...
@@ -37,37 +41,58 @@ func (l *Format) SetBasicSurveyor(af *ecg.BasicSurveyor) {
 	l.everyFileSurveyor = af
 }
 
+// SetThresholds ...
+func (l *Format) SetThresholds(t ecg.Thresholds) {
+	l.thresholds = t
+}
+
 // Init ...
 func (l *Format) Init() ([]*ecg.SummaryResult, error) {
 	return nil, nil
 }
 
-// RunFile ...
-func (l *Format) RunFile(f *ecg.File) ([]*ecg.SummaryResult, error) {
-	var match []string
+// globsFor the group of globs the file name matches, nil if none do
+func globsFor(filename string) []string {
+	_, fn := filepath.Split(filename)
 	for _, gs := range globs {
-		_, fn := filepath.Split(f.Filename)
 		for _, gss := range gs {
-			if m, err := filepath.Match(gss, fn); err != nil {
-				return nil, err
-			} else if m {
-				match = gs
-				break
+			if m, _ := filepath.Match(gss, fn); m {
+				return gs
 			}
 		}
-		if len(match) > 0 {
-			break
-		}
 	}
-	if len(match) == 0 {
+	return nil
+}
+
+// Claims ...
+func (l *Format) Claims(f *ecg.File) bool {
+	return globsFor(f.Filename) != nil || f.HasFiletype(filetypes)
+}
+
+// RunFile ...
+func (l *Format) RunFile(f *ecg.File) ([]*ecg.SummaryResult, error) {
+	match := globsFor(f.Filename)
+	if len(match) == 0 && !f.HasFiletype(filetypes) {
 		return nil, nil
 	}
 	l.matches++
-	globstr := strings.Join(match, ":")
-	surveyor, ok := l.surveyor[globstr]
-	if !ok {
-		surveyor = ecg.NewBasicSurveyor()
-		l.surveyor[globstr] = surveyor
+	var surveyor *ecg.BasicSurveyor
+	if len(match) == 0 {
+		if l.classified == nil {
+			l.classified = ecg.NewBasicSurveyor()
+			l.classified.SetThresholds(l.thresholds)
+		}
+		surveyor = l.classified
+		l.paths = append(l.paths, ecg.ExactGlob(f.Filename))
+	} else {
+		globstr := strings.Join(match, ":")
+		var ok bool
+		surveyor, ok = l.surveyor[globstr]
+		if !ok {
+			surveyor = ecg.NewBasicSurveyor()
+			surveyor.SetThresholds(l.thresholds)
+			l.surveyor[globstr] = surveyor
+		}
 	}
 	_, _, _, err := surveyor.ReadFile(f)
 	if err != nil {
@@ -90,12 +115,28 @@ func (l *Format) End() ([]*ecg.SummaryResult, error) {
 		surveyor.Summarize()
 		results = append(results, &ecg.SummaryResult{
 			FileGlobs:  gs,
-			Confidence: 1,
+			Confidence: surveyor.OverallConfidence(),
 			Template: &Surveyor{
 				everyFileSurveyor: l.everyFileSurveyor,
 				surveyor:          surveyor,
 			},
-			Path: "/",
+			Path:     "/",
+			Surveyor: surveyor,
+		})
+	}
+	if l.classified != nil {
+		// Files arrive in whatever order they were read in
+		sort.Strings(l.paths)
+		l.classified.Summarize()
+		results = append(results, &ecg.SummaryResult{
+			FileGlobs:  l.paths,
+			Confidence: l.classified.OverallConfidence(),
+			Template: &Surveyor{
+				everyFileSurveyor: l.everyFileSurveyor,
+				surveyor:          l.classified,
+			},
+			Path:     "/",
+			Surveyor: l.classified,
 		})
 	}
 	return results, nil
@@ -110,57 +151,20 @@ type Surveyor struct {
 // String ...
 func (l *Surveyor) String() (string, error) {
 	b := bytes.NewBuffer(nil)
-	t := template.Must(template.New("").Parse(string(ectemplate)))
-	var allFiles *ecg.BasicSurveyor
-	if l.everyFileSurveyor == nil {
-		allFiles = l.surveyor
-	} else {
-		allFiles = ecg.NewBasicSurveyor()
-		if l.everyFileSurveyor.InsertFinalNewline != l.surveyor.InsertFinalNewline {
-			allFiles.InsertFinalNewline = l.surveyor.InsertFinalNewline
-		}
-		if l.everyFileSurveyor.Charset != l.surveyor.Charset {
-			allFiles.Charset = l.surveyor.Charset
-		}
-		if l.everyFileSurveyor.Charsets != l.surveyor.Charsets {
-			allFiles.Charsets = l.surveyor.Charsets
-		}
-		if l.everyFileSurveyor.TrimTrailingWhitespace != l.surveyor.TrimTrailingWhitespace {
-			allFiles.TrimTrailingWhitespace = l.surveyor.TrimTrailingWhitespace
-		}
-		if l.everyFileSurveyor.EndOfLine != l.surveyor.EndOfLine {
-			allFiles.EndOfLine = l.surveyor.EndOfLine
-		}
-		if l.everyFileSurveyor.Files != l.surveyor.Files {
-			allFiles.Files = l.surveyor.Files
-		}
-		if l.everyFileSurveyor.CharacterSets != l.surveyor.CharacterSets {
-			allFiles.CharacterSets = l.surveyor.CharacterSets
-		}
-
-		if l.everyFileSurveyor.IndentStyle != l.surveyor.IndentStyle {
-			allFiles.IndentStyle = l.surveyor.IndentStyle
-		}
-		if l.everyFileSurveyor.IndentSize != l.surveyor.IndentSize {
-			allFiles.IndentSize = l.surveyor.IndentSize
-		}
-		if l.everyFileSurveyor.MaxLineLength != l.surveyor.MaxLineLength {
-			allFiles.MaxLineLength = l.surveyor.MaxLineLength
-		}
-		if l.everyFileSurveyor.TabWidth != l.surveyor.TabWidth {
-			allFiles.TabWidth = l.surveyor.TabWidth
-		}
-	}
-	err := t.Execute(b, allFiles)
+	t := template.Must(template.New("").Parse(string(ecg.SurveyedTemplate)))
+	err := t.Execute(b, l.surveyor.Delta(l.everyFileSurveyor))
 	return b.String(), err
 }
 
 func init() {
 	ecg.Register(func() ecg.FileFormat {
 		return ecg.NewContainer("Shell", &Format{
-			surveyor: map[string]*ecg.BasicSurveyor{},
+			surveyor:   map[string]*ecg.BasicSurveyor{},
+			thresholds: ecg.DefaultThresholds(),
 		})
 	})
 }
 
 var _ ecg.BasicSurveyorSetter = (*Format)(nil)
+var _ ecg.FileClaimer = (*Format)(nil)
+var _ ecg.ThresholdsSetter = (*Format)(nil)
@@ -0,0 +1,230 @@
+package ecg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+// fixableProperties the properties FixContent can bring a file in line with
+var fixableProperties = map[string]bool{
+	"indent_style":             true,
+	"end_of_line":              true,
+	"trim_trailing_whitespace": true,
+	"insert_final_newline":     true,
+}
+
+// Fix the changes needed to make a file conform to its properties
+type Fix struct {
+	Filename string
+	// The contents of the file as it is
+	Before []byte
+	// The contents of the file once fixed
+	After []byte
+	// What CheckFile found wrong with the file, limited to the properties which can be fixed
+	Violations []*Violation
+}
+
+// Fixes reads every file in dir which isn't ignored and works out how to make it conform to the editorconfig files,
+// files which already conform are left out. To fix files against a guess wrap it with `EditorConfigs{".": guess}`.
+// Nothing is written, see Fix.After.
+// Files which can't be read are left out and the rest are still fixed, they are returned joined together as FileErrors
+// along with the fixes of the rest. WithStrict stops at the first instead.
+func Fixes(dir fs.FS, configs EditorConfigs, ignore func(file *File) bool, opts ...Option) ([]*Fix, error) {
+	var fixes []*Fix
+	var errs []error
+	o := newAnalyzeOptions(opts)
+	fail := func(fe *FileError) error {
+		if o.strict {
+			return fe
+		}
+		errs = append(errs, fe)
+		return nil
+	}
+	err := fs.WalkDir(dir, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil && path == "." {
+			return err
+		}
+		if err != nil {
+			return fail(&FileError{Filename: path, Err: err})
+		}
+		if skip, err := o.skip(path, d); skip {
+			return err
+		}
+		if d.IsDir() || d.Name() == EditorConfigFilename {
+			return nil
+		}
+		f := &File{
+			Filename:   path,
+			FileOpener: dir,
+		}
+		if ignore(f) {
+			return nil
+		}
+		properties := configs.PropertiesFor(path)
+		if len(properties) == 0 {
+			return nil
+		}
+		fix, err := FixFile(f, properties)
+		if err != nil {
+			return fail(&FileError{Filename: path, Err: err})
+		}
+		if fix != nil {
+			fixes = append(fixes, fix)
+		}
+		return nil
+	})
+	if err != nil {
+		return fixes, fmt.Errorf("fixing: %w", err)
+	}
+	return fixes, errors.Join(errs...)
+}
+
+// FixFile works out how to make a single file conform to the properties, nil if it already does
+func FixFile(f *File, properties map[string]string) (*Fix, error) {
+	violations, err := CheckFile(f, properties)
+	if err != nil {
+		return nil, err
+	}
+	before, err := fs.ReadFile(f.FileOpener, f.Filename)
+	if err != nil {
+		return nil, err
+	}
+	after := FixContent(before, properties)
+	if bytes.Equal(before, after) {
+		return nil, nil
+	}
+	fix := &Fix{
+		Filename: f.Filename,
+		Before:   before,
+		After:    after,
+	}
+	for _, v := range violations {
+		if fixableProperties[v.Property] {
+			fix.Violations = append(fix.Violations, v)
+		}
+	}
+	return fix, nil
+}
+
+// FixContent rewrites the content to conform to indent_style, end_of_line, trim_trailing_whitespace and
+// insert_final_newline. Only the indentation at the start of lines is changed, spaces left over when converting to
+// tabs are kept for alignment.
+func FixContent(content []byte, properties map[string]string) []byte {
+	if len(content) == 0 {
+		return content
+	}
+	indentStyle := normalizeValue(properties["indent_style"])
+	indentWidth, tabWidth := indentWidths(properties)
+	trim := strings.EqualFold(properties["trim_trailing_whitespace"], "true")
+	var eol []byte
+	switch strings.ToLower(properties["end_of_line"]) {
+	case "lf":
+		eol = []byte("\n")
+	case "crlf":
+		eol = []byte("\r\n")
+	}
+	out := make([]byte, 0, len(content))
+	var lastEOL []byte
+	for rest := content; len(rest) > 0; {
+		line := rest
+		var lineEOL []byte
+		if i := bytes.IndexByte(rest, '\n'); i >= 0 {
+			line = rest[:i]
+			lineEOL = []byte("\n")
+			rest = rest[i+1:]
+			if bytes.HasSuffix(line, []byte("\r")) {
+				line = line[:len(line)-1]
+				lineEOL = []byte("\r\n")
+			}
+		} else {
+			rest = nil
+		}
+		if trim {
+			line = bytes.TrimRight(line, " \t")
+		}
+		switch indentStyle {
+		case "tab":
+			line = reindent(line, indentWidth, true)
+		case "space":
+			line = reindent(line, tabWidth, false)
+		}
+		out = append(out, line...)
+		if lineEOL != nil {
+			if eol != nil {
+				lineEOL = eol
+			}
+			out = append(out, lineEOL...)
+			if lastEOL == nil {
+				lastEOL = lineEOL
+			}
+		}
+	}
+	switch strings.ToLower(properties["insert_final_newline"]) {
+	case "true":
+		if len(out) > 0 && !bytes.HasSuffix(out, []byte("\n")) {
+			switch {
+			case eol != nil:
+				out = append(out, eol...)
+			case lastEOL != nil:
+				// Match the rest of the file
+				out = append(out, lastEOL...)
+			default:
+				out = append(out, '\n')
+			}
+		}
+	case "false":
+		out = bytes.TrimRight(out, "\r\n")
+	}
+	return out
+}
+
+// indentWidths the number of columns of an indent level and of a tab, each defaulting to the other and then 4
+func indentWidths(properties map[string]string) (int, int) {
+	indentWidth, err := strconv.Atoi(properties["indent_size"])
+	if err != nil || indentWidth <= 0 {
+		indentWidth = 0
+	}
+	tabWidth, err := strconv.Atoi(properties["tab_width"])
+	if err != nil || tabWidth <= 0 {
+		tabWidth = indentWidth
+	}
+	if indentWidth == 0 {
+		indentWidth = tabWidth
+	}
+	if indentWidth == 0 {
+		indentWidth, tabWidth = 4, 4
+	}
+	return indentWidth, tabWidth
+}
+
+// reindent rewrites the whitespace at the start of the line as tabs and alignment spaces or as spaces only, width is
+// the columns a tab takes up
+func reindent(line []byte, width int, tabs bool) []byte {
+	end := 0
+	column := 0
+loop:
+	for ; end < len(line); end++ {
+		switch line[end] {
+		case ' ':
+			column++
+		case '\t':
+			column += width - column%width
+		default:
+			break loop
+		}
+	}
+	var prefix []byte
+	if tabs {
+		prefix = append(bytes.Repeat([]byte("\t"), column/width), bytes.Repeat([]byte(" "), column%width)...)
+	} else {
+		prefix = bytes.Repeat([]byte(" "), column)
+	}
+	if bytes.Equal(prefix, line[:end]) {
+		return line
+	}
+	return append(prefix, line[end:]...)
+}
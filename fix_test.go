@@ -0,0 +1,65 @@
+package ecg
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFixContent(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		properties map[string]string
+		want       string
+	}{
+		{name: "Nothing to do", content: "a \r\n", properties: map[string]string{}, want: "a \r\n"},
+		{name: "CRLF to LF", content: "a\r\nb\r\n", properties: map[string]string{"end_of_line": "lf"}, want: "a\nb\n"},
+		{name: "LF to CRLF", content: "a\nb\r\n", properties: map[string]string{"end_of_line": "crlf"}, want: "a\r\nb\r\n"},
+		{name: "Trailing whitespace", content: "a \t\r\n  \nb", properties: map[string]string{"trim_trailing_whitespace": "true"}, want: "a\r\n\nb"},
+		{name: "Final new line matches the file", content: "a\r\nb", properties: map[string]string{"insert_final_newline": "true"}, want: "a\r\nb\r\n"},
+		{name: "Final new line single line", content: "a", properties: map[string]string{"insert_final_newline": "true"}, want: "a\n"},
+		{name: "No final new line", content: "a\n\n", properties: map[string]string{"insert_final_newline": "false"}, want: "a"},
+		{name: "Spaces to tabs", content: "a\n    b\n      c\n  \t d\n", properties: map[string]string{"indent_style": "tab", "indent_size": "4"}, want: "a\n\tb\n\t  c\n\t d\n"},
+		{name: "Tabs to spaces", content: "a\n\tb\n\t\tc  \t\n", properties: map[string]string{"indent_style": "space", "indent_size": "2"}, want: "a\n  b\n    c  \t\n"},
+		{name: "Guessed style", content: "\tb\n", properties: map[string]string{"indent_style": "spaces", "tab_width": "8"}, want: "        b\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, string(FixContent([]byte(tt.content), tt.properties))); diff != "" {
+				t.Errorf("FixContent() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFixes(t *testing.T) {
+	mapFS := fstest.MapFS{
+		".editorconfig": &fstest.MapFile{Data: []byte("root = true\n[*]\nend_of_line = lf\ninsert_final_newline = true\ntrim_trailing_whitespace = true\n[*.go]\nindent_style = tab\nmax_line_length = 20\n")},
+		"good.go":       &fstest.MapFile{Data: []byte("package main\n\nfunc main() {\n\tprintln()\n}\n")},
+		"bad.go":        &fstest.MapFile{Data: []byte("package main \r\n\r\nfunc main() {\r\n    println(\"a long line\")\r\n}")},
+	}
+	configs, err := LoadEditorConfigs(mapFS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixes, err := Fixes(mapFS, configs, func(file *File) bool { return false })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixes) != 1 {
+		t.Fatalf("Fixes() got %d fixes, want 1", len(fixes))
+	}
+	if got, want := string(fixes[0].After), "package main\n\nfunc main() {\n\tprintln(\"a long line\")\n}\n"; got != want {
+		t.Errorf("Fixes() After = %q, want %q", got, want)
+	}
+	var got []string
+	for _, v := range fixes[0].Violations {
+		got = append(got, v.Property)
+	}
+	want := []string{"end_of_line", "indent_style", "insert_final_newline", "trim_trailing_whitespace"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Fixes() violations mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -10,6 +10,7 @@ require (
 	github.com/saintfish/chardet v0.0.0-20230101081208-5e3ef4b5456d
 	golang.org/x/exp v0.0.0-20260611194520-c48552f49976
 	golang.org/x/tools v0.46.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
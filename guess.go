@@ -0,0 +1,239 @@
+package ecg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Guess the structured result of Analyze, String() renders it as an editorconfig file
+type Guess struct {
+	// Lines before the first section, ie the `root = true` header
+	Preamble []*Property
+	// Sections in the order they are rendered
+	Sections []*Section
+	// How many files Analyze surveyed, 0 for a parsed editorconfig file
+	Files int
+	// The files which couldn't be surveyed and the FileFormats which failed, sorted by filename
+	Errors []*FileError
+	// What the FileFormats noticed about the files which doesn't belong in the editorconfig file, sorted by filename
+	Warnings []*Warning
+}
+
+// Section a `[glob]` section of an editorconfig file
+type Section struct {
+	// Name of the FileFormat which produced this section
+	Format string
+	// Impacted globs
+	FileGlobs []string
+	// The directory the section was surveyed from, "/" for the whole project
+	Path string
+	// How confident the FileFormat was in the section as a whole
+	Confidence float64
+	// Properties, comments and blank lines in the order they are rendered
+	Properties []*Property
+	// The statistics behind the section, nil for formats which don't survey the files (ie Presence)
+	Surveyor *BasicSurveyor
+	// The result the section was built from, nil if it was parsed
+	Result *SummaryResult
+	// The sections Consolidate merged into this one
+	Merged []*Section
+	// Rendered after two more blank lines, sections from the same FileFormat have always been spaced out further
+	Spaced bool
+}
+
+// Property a single line of a section, a Property without a Key is a comment or blank line
+type Property struct {
+	Key        string
+	Value      string
+	Comment    string
+	Confidence float64
+	// The line as it was read, used to render it unchanged
+	raw string
+}
+
+// RenderOptions controls how a Guess is rendered
+type RenderOptions struct {
+	// Adds the confidence of each property as a comment line before it
+	ShowConfidence bool
+	// Leaves out properties with a confidence below this (0 to 1)
+	MinConfidence float64
+}
+
+// String renders the guess as an editorconfig file
+func (g *Guess) String() string {
+	return g.Render(RenderOptions{})
+}
+
+// Render renders the guess as an editorconfig file
+func (g *Guess) Render(opts RenderOptions) string {
+	sb := &strings.Builder{}
+	for _, p := range g.Preamble {
+		sb.WriteString(p.String())
+		sb.WriteString("\n")
+	}
+	for i, s := range g.Sections {
+		if i > 0 && s.Spaced {
+			sb.WriteString("\n\n")
+		}
+		sb.WriteString(s.Render(opts))
+	}
+	return sb.String()
+}
+
+// Section finds the first section with the header, ie `*.go` or `{*.ts,*.js}`
+func (g *Guess) Section(header string) *Section {
+	for _, s := range g.Sections {
+		if s.Header() == header {
+			return s
+		}
+	}
+	return nil
+}
+
+// Header the section header without the square brackets
+func (s *Section) Header() string {
+	if len(s.FileGlobs) == 1 {
+		return s.FileGlobs[0]
+	}
+	return "{" + strings.Join(s.FileGlobs, ",") + "}"
+}
+
+// String the section as it would appear in an editorconfig file
+func (s *Section) String() string {
+	return s.Render(RenderOptions{})
+}
+
+// Render the section as it would appear in an editorconfig file
+func (s *Section) Render(opts RenderOptions) string {
+	sb := &strings.Builder{}
+	sb.WriteString("[")
+	sb.WriteString(s.Header())
+	sb.WriteString("]\n")
+	for _, p := range s.Properties {
+		if p.Key != "" && p.Confidence < opts.MinConfidence {
+			continue
+		}
+		if p.Key != "" && opts.ShowConfidence {
+			sb.WriteString(p.ConfidenceComment().String())
+			sb.WriteString("\n")
+		}
+		sb.WriteString(p.String())
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// Surveyors the statistics behind the section and the sections merged into it
+func (s *Section) Surveyors() []*BasicSurveyor {
+	var result []*BasicSurveyor
+	if s.Surveyor != nil {
+		result = append(result, s.Surveyor)
+	}
+	for _, m := range s.Merged {
+		result = append(result, m.Surveyors()...)
+	}
+	return result
+}
+
+// Get returns the property with the key or nil
+func (s *Section) Get(key string) *Property {
+	key = strings.ToLower(key)
+	for _, p := range s.Properties {
+		if p.Key == key {
+			return p
+		}
+	}
+	return nil
+}
+
+// Values the key value pairs of the section, comments and blank lines are left out
+func (s *Section) Values() map[string]string {
+	r := map[string]string{}
+	for _, p := range s.Properties {
+		if p.Key != "" {
+			r[p.Key] = p.Value
+		}
+	}
+	return r
+}
+
+// IsBlank true if the line is empty
+func (p *Property) IsBlank() bool {
+	return p.Key == "" && p.Comment == ""
+}
+
+// IsComment true if the line is only a comment
+func (p *Property) IsComment() bool {
+	return p.Key == "" && p.Comment != ""
+}
+
+// ConfidenceComment a comment line giving the property's confidence, it goes before the property as editorconfig
+// values run to the end of the line
+func (p *Property) ConfidenceComment() *Property {
+	return &Property{Comment: fmt.Sprintf("confidence %0.0f%%", p.Confidence*100)}
+}
+
+// String the line as it would appear in an editorconfig file
+func (p *Property) String() string {
+	if p.raw != "" {
+		if o := ParseProperty(p.raw); o.Key == p.Key && o.Value == p.Value && o.Comment == p.Comment {
+			return p.raw
+		}
+	}
+	sb := &strings.Builder{}
+	if p.Key != "" {
+		sb.WriteString(p.Key)
+		sb.WriteString(" = ")
+		sb.WriteString(p.Value)
+		if p.Comment != "" {
+			sb.WriteString(" ")
+		}
+	}
+	if p.Comment != "" {
+		sb.WriteString("# ")
+		sb.WriteString(p.Comment)
+	}
+	return sb.String()
+}
+
+// ParseProperty parses a single `key = value # comment` line, keys are lower cased as per the specification. The
+// specification makes everything after the `=` the value, this is deliberately more lenient: a ` #` or ` ;` starts a
+// comment, as the templates write them after values and older parsers accepted them. A value holding either of them
+// is cut short there, String still writes the line back as it was read.
+func ParseProperty(line string) *Property {
+	p := &Property{raw: line}
+	body := strings.TrimSpace(line)
+	if body == "" {
+		p.raw = ""
+		return p
+	}
+	if body[0] == '#' || body[0] == ';' {
+		p.Comment = strings.TrimSpace(body[1:])
+		return p
+	}
+	if i := strings.Index(body, " #"); i >= 0 {
+		p.Comment = strings.TrimSpace(body[i+2:])
+		body = body[:i]
+	} else if i := strings.Index(body, " ;"); i >= 0 {
+		p.Comment = strings.TrimSpace(body[i+2:])
+		body = body[:i]
+	}
+	k, v, _ := strings.Cut(body, "=")
+	p.Key = strings.ToLower(strings.TrimSpace(k))
+	p.Value = strings.TrimSpace(v)
+	return p
+}
+
+// ParseProperties parses the lines of a section body, a single trailing new line is ignored
+func ParseProperties(body string) []*Property {
+	body = strings.TrimSuffix(strings.ReplaceAll(body, "\r\n", "\n"), "\n")
+	if body == "" {
+		return nil
+	}
+	lines := strings.Split(body, "\n")
+	r := make([]*Property, 0, len(lines))
+	for _, l := range lines {
+		r = append(r, ParseProperty(l))
+	}
+	return r
+}
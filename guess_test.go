@@ -0,0 +1,78 @@
+package ecg
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseProperty(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want *Property
+	}{
+		{name: "Blank", line: "", want: &Property{}},
+		{name: "Whitespace only", line: "  \t", want: &Property{}},
+		{name: "Hash comment", line: "# charset = ???  (100.0%)", want: &Property{Comment: "charset = ???  (100.0%)"}},
+		{name: "Semicolon comment", line: "; a comment", want: &Property{Comment: "a comment"}},
+		{name: "Property", line: "indent_style = tab", want: &Property{Key: "indent_style", Value: "tab"}},
+		{name: "Property without spaces", line: "indent_size=4", want: &Property{Key: "indent_size", Value: "4"}},
+		{name: "Property key is lower cased", line: "Indent_Style = Tab", want: &Property{Key: "indent_style", Value: "Tab"}},
+		{name: "Property with comment", line: "charset = utf-8 # UTF-8 (100.0%)", want: &Property{Key: "charset", Value: "utf-8", Comment: "UTF-8 (100.0%)"}},
+		{name: "Property with semicolon comment", line: "indent_size = 4 ; four", want: &Property{Key: "indent_size", Value: "4", Comment: "four"}},
+		{name: "Hash without a space is part of the value", line: "x_marker = a#b;c", want: &Property{Key: "x_marker", Value: "a#b;c"}},
+		{name: "Hash after a space cuts the value short", line: "x_marker = a #b", want: &Property{Key: "x_marker", Value: "a", Comment: "b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseProperty(tt.line)
+			if diff := cmp.Diff(tt.want, got, cmp.AllowUnexported(Property{}), cmpIgnoreRaw); diff != "" {
+				t.Errorf("ParseProperty() mismatch (-want +got):\n%s", diff)
+			}
+			if s := got.String(); s != tt.line && !got.IsBlank() {
+				t.Errorf("String() = %q, want %q", s, tt.line)
+			}
+		})
+	}
+}
+
+var cmpIgnoreRaw = cmp.FilterPath(func(p cmp.Path) bool {
+	return p.Last().String() == ".raw"
+}, cmp.Ignore())
+
+func TestProperty_String(t *testing.T) {
+	p := ParseProperty("indent_size=4 ;two")
+	p.Value = "2"
+	if got, want := p.String(), "indent_size = 2 # two"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestSection_Render(t *testing.T) {
+	s := &Section{
+		FileGlobs: []string{"*.ts", "*.js"},
+		Properties: []*Property{
+			{Key: "indent_style", Value: "space", Confidence: 0.9},
+			{Comment: "charset = ???"},
+			{Key: "indent_size", Value: "2", Confidence: 0.3},
+			{},
+		},
+	}
+	tests := []struct {
+		name string
+		opts RenderOptions
+		want string
+	}{
+		{name: "Default", want: "[{*.ts,*.js}]\nindent_style = space\n# charset = ???\nindent_size = 2\n\n"},
+		{name: "Show confidence", opts: RenderOptions{ShowConfidence: true}, want: "[{*.ts,*.js}]\n# confidence 90%\nindent_style = space\n# charset = ???\n# confidence 30%\nindent_size = 2\n\n"},
+		{name: "Minimum confidence", opts: RenderOptions{MinConfidence: 0.5}, want: "[{*.ts,*.js}]\nindent_style = space\n# charset = ???\n\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, s.Render(tt.opts)); diff != "" {
+				t.Errorf("Render() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -1,14 +1,22 @@
 package cli
 
 import (
+	"context"
 	ecg "editorconfig-guesser"
 	_ "editorconfig-guesser/fileformats"
+	"editorconfig-guesser/internal/udiff"
+	"errors"
 	"fmt"
 	"github.com/denormal/go-gitignore"
+	"golang.org/x/exp/maps"
+	"io/fs"
 	"log"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 )
 
 // Root is a subcommand `ecguess`
@@ -17,62 +25,515 @@ func Root() {}
 // Generate is a subcommand `ecguess generate`
 // Flags:
 // 	saveFlag: -s --save (default: false) Save the file as .editorconfig
+// 	mergeFlag: --merge (default: false) Merge confident properties into the existing .editorconfig keeping everything else
 // 	verboseFlag: -v --verbose (default: false) Logs more than what is required
+// 	confidenceFlag: --confidence (default: false) Show the confidence of each property as a comment
+// 	minConfidence: --min-confidence (default: -1) Leave out properties with a confidence below this (0 to 1), the config's if not given
+// 	subdirectories: --subdirectories (default: -1) How many directories deep to look for subtrees which differ from their parent, the config's if not given
+// 	nestedFlag: --nested (default: false) Put subtree sections in their own nested .editorconfig files
+// 	format: --format (default: ) Output format: editorconfig, json or yaml, editorconfig unless the config sets it
+// 	sampling: --sampling (default: full) Which parts of each file to survey: full, head or head-middle-tail
+// 	jobs: -j --jobs (default: 0) How many files to read at once, 0 uses every CPU
+// 	readTimeout: --read-timeout (default: ) Skip files which take longer than this to read, ie 5s
+// 	strictFlag: --strict (default: false) Stop at the first file which can't be read instead of leaving it out
+// 	configFile: --config (default: ) Config file read after the user and project ones
+// 	thresholds: --threshold (default: ) Comma separated name=value thresholds, ie final_newline=0.9
+// 	formats: --formats (default: ) Comma separated file formats to run, every format if empty
+// 	disableFormats: --disable-formats (default: ) Comma separated file formats not to run
+// 	ignorePatterns: --ignore (default: ) Comma separated gitignore style patterns of files to leave out
 // 	args: ... Directories
 //
-func Generate(saveFlag bool, verboseFlag bool, args ...string) {
+func Generate(saveFlag bool, mergeFlag bool, verboseFlag bool, confidenceFlag bool, minConfidence float64, subdirectories int, nestedFlag bool, format string, sampling string, jobs int, readTimeout string, strictFlag bool, configFile string, thresholds string, formats string, disableFormats string, ignorePatterns string, args ...string) error {
 	log.SetFlags(log.Flags() | log.Lshortfile)
 	if len(args) == 0 {
-		fmt.Println("Please provide at least one directory")
+		return usageError("please provide at least one directory")
 	}
-	for _, e := range args {
-		ignore, err := gitignore.NewRepository(e)
+	samplePolicy, err := ecg.ParseSamplePolicy(sampling)
+	if err != nil {
+		return usageError("%w", err)
+	}
+	var timeout time.Duration
+	if readTimeout != "" {
+		if timeout, err = time.ParseDuration(readTimeout); err != nil {
+			return usageError("invalid read timeout %s: %w", readTimeout, err)
+		}
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	// The directories without files, the others are still guessed
+	var empty []string
+	overrides := Overrides{
+		Format:         format,
+		Confidence:     confidenceFlag,
+		Nested:         nestedFlag,
+		Thresholds:     thresholds,
+		Formats:        formats,
+		DisableFormats: disableFormats,
+		Ignore:         ignorePatterns,
+	}
+	if minConfidence != notGiven {
+		overrides.MinConfidence = &minConfidence
+	}
+	if subdirectories != notGiven {
+		overrides.Subdirectories = &subdirectories
+	}
+	for i, e := range args {
+		config, err := loadConfig(e, configFile, overrides)
 		if err != nil {
-			log.Printf("Loading git ignores failed: %s", err)
-			ignore = nil
+			return err
 		}
-
-		template, err := ecg.RunInDir(os.DirFS(e), func(file *ecg.File) bool {
-			for _, part := range filepath.SplitList(file.Filename) {
-				if strings.HasPrefix(part, ".") && part != "." {
-					if verboseFlag {
-						log.Printf("Skipping %s as it has a hidden file in the path: %s", file.Filename, part)
-					}
-					return true
+		output := config.Output
+		opts := append([]ecg.Option{
+			ecg.WithWalkFilter(newWalkFilter(e, verboseFlag, config)),
+			ecg.WithSubdirectories(output.Subdirectories, 0),
+			ecg.WithSampling(samplePolicy),
+			ecg.WithJobs(jobs),
+			ecg.WithReadTimeout(timeout),
+		}, config.Options()...)
+		if strictFlag {
+			opts = append(opts, ecg.WithStrict())
+		}
+		// Verbose logging would break up the line
+		progress := newProgressLine(e)
+		if progress != nil && !verboseFlag {
+			opts = append(opts, ecg.WithProgress(progress.Update))
+		}
+		guess, err := ecg.AnalyzeContext(ctx, os.DirFS(e), newIgnore(verboseFlag), opts...)
+		progress.Clear()
+		if err != nil {
+			return analysisError(err)
+		}
+		reportErrors(e, guess)
+		if guess.Files == 0 {
+			empty = append(empty, e)
+			continue
+		}
+		if output.Format != "editorconfig" {
+			report := guess.Report(ecg.RenderOptions{MinConfidence: output.MinConfidence})
+			report.Directory = e
+			b, err := report.Marshal(output.Format)
+			if err != nil {
+				return err
+			}
+			if i > 0 && output.Format == "yaml" {
+				fmt.Println("---")
+			}
+			fmt.Print(string(b))
+		}
+		guesses := map[string]*ecg.Guess{"/": guess}
+		if output.Nested {
+			guesses = guess.Split()
+		}
+		paths := maps.Keys(guesses)
+		sort.Strings(paths)
+		for _, p := range paths {
+			template := guesses[p].Render(ecg.RenderOptions{
+				ShowConfidence: output.Confidence,
+				MinConfidence:  output.MinConfidence,
+			})
+			outfn := filepath.Join(e, filepath.FromSlash(p), ".editorconfig")
+			if !saveFlag && output.Format != "editorconfig" {
+				continue
+			}
+			if mergeFlag {
+				merged, err := mergeInto(outfn, guesses[p], mergeConfidence(overrides, output))
+				if err != nil {
+					return analysisError(fmt.Errorf("merging %s: %w", outfn, err))
+				}
+				if merged != "" {
+					template = merged
 				}
 			}
-			if ignore != nil && ignore.Ignore(filepath.Join(e, file.Filename)) {
-				if verboseFlag {
-					log.Printf("Skipping %s as it is in the .gitignore file", file.Filename)
+			if output.Format == "editorconfig" {
+				if len(args) > 1 || len(paths) > 1 {
+					fmt.Println("// ", outfn)
+				}
+				fmt.Println(template)
+				if len(args) > 1 || len(paths) > 1 {
+					fmt.Println()
+					fmt.Println()
 				}
-				return true
 			}
-			if file.IsBinary() {
-				if verboseFlag {
-					log.Printf("Skipping %s as it is considered a binary file", file.Filename)
+			if saveFlag {
+				if err := os.WriteFile(outfn, []byte(template), 0644); err != nil {
+					return writeError(fmt.Errorf("saving %s: %w", outfn, err))
 				}
-				return true
+				log.Println("Wrote: ", outfn)
 			}
-			return false
-		})
+		}
+	}
+	if len(empty) > 0 {
+		return &ExitError{Code: ExitNothingToGuess, Err: fmt.Errorf("%s: %w", strings.Join(empty, ", "), ErrNothingToGuess)}
+	}
+	return nil
+}
+
+// MergeMinConfidence the confidence --merge needs unless --min-confidence is given, replacing what someone wrote takes
+// more certainty than guessing from scratch
+const MergeMinConfidence = 0.8
+
+// notGiven the default of the number flags which fall back to the config, see Overrides
+const notGiven = -1
+
+// mergeConfidence the confidence a property needs to replace what is in an existing .editorconfig, --min-confidence if
+// it was given and otherwise at least MergeMinConfidence
+func mergeConfidence(o Overrides, output OutputConfig) float64 {
+	if o.MinConfidence != nil {
+		return *o.MinConfidence
+	}
+	return max(output.MinConfidence, MergeMinConfidence)
+}
+
+// mergeInto merges the guess into the editorconfig file if there is one, logging each change, and returns the result
+func mergeInto(fn string, guess *ecg.Guess, minConfidence float64) (string, error) {
+	f, err := os.Open(fn)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	existing, err := ecg.ParseEditorConfig(f)
+	if err != nil {
+		return "", err
+	}
+	changes := ecg.Merge(existing, guess, minConfidence)
+	for _, c := range changes {
+		log.Printf("%s: %s", fn, c)
+	}
+	if len(changes) == 0 {
+		log.Printf("%s: nothing to change", fn)
+	}
+	return existing.String(), nil
+}
+
+// Check is a subcommand `ecguess check`
+// Flags:
+// 	verboseFlag: -v --verbose (default: false) Logs more than what is required
+// 	configFile: --config (default: ) Config file read after the user and project ones
+// 	args: ... Directories
+//
+func Check(verboseFlag bool, configFile string, args ...string) error {
+	log.SetFlags(log.Flags() | log.Lshortfile)
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+	failed := 0
+	// The directories with files which couldn't be read
+	unreadable := 0
+	for _, e := range args {
+		config, err := loadConfig(e, configFile, Overrides{})
 		if err != nil {
-			log.Panicf("Error: %s", err)
+			return err
 		}
-		if len(args) > 1 {
-			fmt.Println("// ", e)
+		dir := os.DirFS(e)
+		filter := ecg.WithWalkFilter(newWalkFilter(e, verboseFlag, config))
+		configs, err := ecg.LoadEditorConfigs(dir, filter)
+		if err != nil {
+			return analysisError(fmt.Errorf("loading %s: %w", ecg.EditorConfigFilename, err))
 		}
-		fmt.Println(template)
-		if len(args) > 1 {
-			fmt.Println()
-			fmt.Println()
+		if len(configs) == 0 {
+			return &ExitError{Code: ExitNoEditorConfig, Err: fmt.Errorf("%s: %w", e, ErrNoEditorConfig)}
 		}
-		if saveFlag {
-			outfn := filepath.Join(e, ".editorconfig")
-			if err := os.WriteFile(outfn, []byte(template), 0644); err != nil {
-				log.Panicf("Error saving %s because %s", outfn, err)
-			} else {
-				log.Println("Wrote: ", outfn)
+		violations, err := ecg.Check(dir, configs, newIgnore(verboseFlag), filter)
+		logged, err := logFileErrors(e, err)
+		if err != nil {
+			return analysisError(err)
+		}
+		if logged {
+			unreadable++
+		}
+		files := map[string]struct{}{}
+		for _, v := range violations {
+			files[v.Filename] = struct{}{}
+			fmt.Printf("%s: %s should be %s but %s\n", filepath.Join(e, v.Filename), v.Property, v.Expected, v.Found)
+		}
+		failed += len(files)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d files do not conform to %s", failed, ecg.EditorConfigFilename)
+	}
+	if unreadable > 0 {
+		return analysisError(fmt.Errorf("%d directories had files which couldn't be checked", unreadable))
+	}
+	return nil
+}
+
+// Diff is a subcommand `ecguess diff`
+// Flags:
+// 	verboseFlag: -v --verbose (default: false) Logs more than what is required
+// 	minConfidence: --min-confidence (default: 0) Ignore guessed properties with a confidence below this (0 to 1)
+// 	configFile: --config (default: ) Config file read after the user and project ones
+// 	args: ... Directories
+//
+func Diff(verboseFlag bool, minConfidence float64, configFile string, args ...string) error {
+	log.SetFlags(log.Flags() | log.Lshortfile)
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+	disagreements := 0
+	for _, e := range args {
+		config, err := loadConfig(e, configFile, Overrides{})
+		if err != nil {
+			return err
+		}
+		dir := os.DirFS(e)
+		filter := ecg.WithWalkFilter(newWalkFilter(e, verboseFlag, config))
+		configs, err := ecg.LoadEditorConfigs(dir, filter)
+		if err != nil {
+			return analysisError(fmt.Errorf("loading %s: %w", ecg.EditorConfigFilename, err))
+		}
+		if len(configs) == 0 {
+			return &ExitError{Code: ExitNoEditorConfig, Err: fmt.Errorf("%s: %w", e, ErrNoEditorConfig)}
+		}
+		guess, err := ecg.Analyze(dir, newIgnore(verboseFlag), append(config.Options(), filter)...)
+		if err != nil {
+			return analysisError(err)
+		}
+		reportErrors(e, guess)
+		for _, d := range ecg.Diff(configs, guess, minConfidence) {
+			if len(args) > 1 {
+				fmt.Printf("%s: ", e)
+			}
+			fmt.Println(d)
+			disagreements++
+		}
+	}
+	if disagreements > 0 {
+		return fmt.Errorf("%d properties differ from the code", disagreements)
+	}
+	return nil
+}
+
+// Outliers is a subcommand `ecguess outliers`
+// Flags:
+// 	verboseFlag: -v --verbose (default: false) Logs more than what is required
+// 	minConfidence: --min-confidence (default: 0) Ignore properties with a confidence below this (0 to 1)
+// 	configFile: --config (default: ) Config file read after the user and project ones
+// 	args: ... Directories
+//
+func Outliers(verboseFlag bool, minConfidence float64, configFile string, args ...string) error {
+	log.SetFlags(log.Flags() | log.Lshortfile)
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+	for _, e := range args {
+		config, err := loadConfig(e, configFile, Overrides{})
+		if err != nil {
+			return err
+		}
+		filter := ecg.WithWalkFilter(newWalkFilter(e, verboseFlag, config))
+		guess, err := ecg.Analyze(os.DirFS(e), newIgnore(verboseFlag), append(config.Options(), filter)...)
+		if err != nil {
+			return analysisError(err)
+		}
+		reportErrors(e, guess)
+		for _, o := range guess.Outliers(minConfidence) {
+			if len(args) > 1 {
+				fmt.Printf("%s: ", e)
+			}
+			fmt.Println(o)
+		}
+	}
+	return nil
+}
+
+// Fix is a subcommand `ecguess fix`
+// Flags:
+// 	dryRunFlag: -n --dry-run (default: false) Print a unified diff of the changes instead of writing them
+// 	guessFlag: --guess (default: false) Fix against the guessed settings even if there is a .editorconfig
+// 	verboseFlag: -v --verbose (default: false) Logs more than what is required
+// 	configFile: --config (default: ) Config file read after the user and project ones
+// 	args: ... Directories
+//
+func Fix(dryRunFlag bool, guessFlag bool, verboseFlag bool, configFile string, args ...string) error {
+	log.SetFlags(log.Flags() | log.Lshortfile)
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+	// The directories with files which couldn't be read
+	unreadable := 0
+	for _, e := range args {
+		config, err := loadConfig(e, configFile, Overrides{})
+		if err != nil {
+			return err
+		}
+		dir := os.DirFS(e)
+		ignore := newIgnore(verboseFlag)
+		filter := ecg.WithWalkFilter(newWalkFilter(e, verboseFlag, config))
+		configs, err := ecg.LoadEditorConfigs(dir, filter)
+		if err != nil {
+			return analysisError(fmt.Errorf("loading %s: %w", ecg.EditorConfigFilename, err))
+		}
+		if guessFlag || len(configs) == 0 {
+			if verboseFlag {
+				log.Printf("Fixing %s against the guessed settings", e)
+			}
+			guess, err := ecg.Analyze(dir, ignore, append(config.Options(), filter)...)
+			if err != nil {
+				return analysisError(err)
+			}
+			reportErrors(e, guess)
+			configs = ecg.EditorConfigs{".": guess}
+		}
+		fixes, err := ecg.Fixes(dir, configs, ignore, filter)
+		logged, err := logFileErrors(e, err)
+		if err != nil {
+			return analysisError(err)
+		}
+		if logged {
+			unreadable++
+		}
+		for _, f := range fixes {
+			fn := filepath.Join(e, filepath.FromSlash(f.Filename))
+			if dryRunFlag {
+				name := filepath.ToSlash(fn)
+				fmt.Print(udiff.Unified(name, name, f.Before, f.After))
+				continue
+			}
+			info, err := os.Stat(fn)
+			if err != nil {
+				return writeError(err)
+			}
+			if err := os.WriteFile(fn, f.After, info.Mode().Perm()); err != nil {
+				return writeError(fmt.Errorf("writing %s: %w", fn, err))
+			}
+			var properties []string
+			for _, v := range f.Violations {
+				properties = append(properties, v.Property)
+			}
+			fmt.Printf("Fixed %s: %s\n", fn, strings.Join(properties, ", "))
+		}
+	}
+	if unreadable > 0 {
+		return analysisError(fmt.Errorf("%d directories had files which couldn't be fixed", unreadable))
+	}
+	return nil
+}
+
+// logFileErrors logs the files Check or Fixes left out as they couldn't be read, true if there were any. Errors other
+// than FileErrors stopped them and are returned.
+func logFileErrors(dir string, err error) (bool, error) {
+	var fe *ecg.FileError
+	if err == nil {
+		return false, nil
+	}
+	if !errors.As(err, &fe) {
+		return false, err
+	}
+	errs := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	}
+	for _, e := range errs {
+		log.Printf("Error: %s: %s", dir, e)
+	}
+	return true, nil
+}
+
+// ShowConfig is a subcommand `ecguess config`
+// Flags:
+// 	configFile: --config (default: ) Config file read after the user and project ones
+// 	thresholds: --threshold (default: ) Comma separated name=value thresholds, ie final_newline=0.9
+// 	formats: --formats (default: ) Comma separated file formats to run, every format if empty
+// 	disableFormats: --disable-formats (default: ) Comma separated file formats not to run
+// 	ignorePatterns: --ignore (default: ) Comma separated gitignore style patterns of files to leave out
+// 	args: ... Directory whose project config is read, the current one if left out
+//
+func ShowConfig(configFile string, thresholds string, formats string, disableFormats string, ignorePatterns string, args ...string) error {
+	dir := "."
+	switch len(args) {
+	case 0:
+	case 1:
+		dir = args[0]
+	default:
+		return usageError("please provide at most one directory")
+	}
+	c, read, err := LoadConfig(dir, configFile)
+	if err != nil {
+		return usageError("%w", err)
+	}
+	if err := c.Override(Overrides{
+		Thresholds:     thresholds,
+		Formats:        formats,
+		DisableFormats: disableFormats,
+		Ignore:         ignorePatterns,
+	}); err != nil {
+		return usageError("%w", err)
+	}
+	if err := c.Validate(); err != nil {
+		return usageError("%w", err)
+	}
+	if len(read) == 0 {
+		fmt.Println("# No config files, these are the defaults")
+	}
+	for _, fn := range read {
+		fmt.Printf("# Read %s\n", fn)
+	}
+	fmt.Print(c)
+	return nil
+}
+
+// reportErrors logs the warnings about files in dir, then what couldn't be surveyed followed by a summary
+func reportErrors(dir string, guess *ecg.Guess) {
+	for _, w := range guess.Warnings {
+		log.Printf("Warning: %s: %s", dir, w)
+	}
+	for _, e := range guess.Errors {
+		log.Printf("Error: %s: %s", dir, e)
+	}
+	if n := len(guess.Errors); n > 0 {
+		log.Printf("%s: %d errors, what failed was left out, --strict stops at the first", dir, n)
+	}
+}
+
+// newWalkFilter prunes hidden files and directories, and those in .gitignore or the config's ignore patterns, so
+// ignored trees such as node_modules are never walked
+func newWalkFilter(dir string, verboseFlag bool, config *Config) ecg.WalkFilter {
+	ignore, err := gitignore.NewRepository(dir)
+	if err != nil {
+		log.Printf("Loading git ignores failed: %s", err)
+		ignore = nil
+	}
+	// Validate has already reported any errors
+	patterns, _ := config.ignorePatterns(dir)
+	return func(path string, d fs.DirEntry) error {
+		if strings.HasPrefix(d.Name(), ".") {
+			if verboseFlag {
+				log.Printf("Skipping %s as it is hidden", path)
+			}
+			return ecg.SkipFile
+		}
+		if ignore != nil {
+			if m := ignore.Relative(filepath.FromSlash(path), d.IsDir()); m != nil && m.Ignore() {
+				if verboseFlag {
+					log.Printf("Skipping %s as it is in the .gitignore file", path)
+				}
+				return ecg.SkipFile
+			}
+		}
+		if m := patterns.Relative(path, d.IsDir()); m != nil && m.Ignore() {
+			if verboseFlag {
+				log.Printf("Skipping %s as it matches the ignore patterns in the config", path)
+			}
+			return ecg.SkipFile
+		}
+		return nil
+	}
+}
+
+// newIgnore the ignore function for RunInDir, it skips binary files, see newWalkFilter for the rest
+func newIgnore(verboseFlag bool) func(file *ecg.File) bool {
+	return func(file *ecg.File) bool {
+		if file.IsBinary() {
+			if verboseFlag {
+				log.Printf("Skipping %s as it is considered a binary file", file.Filename)
 			}
+			return true
 		}
+		return false
 	}
 }
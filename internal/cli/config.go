@@ -0,0 +1,247 @@
+package cli
+
+import (
+	"bytes"
+	ecg "editorconfig-guesser"
+	"errors"
+	"fmt"
+	"github.com/denormal/go-gitignore"
+	"gopkg.in/yaml.v3"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ConfigFilename the project config file, read from the top of each directory ecguess is run on
+const ConfigFilename = ".ecguess.yaml"
+
+// Config what the ecguess config files can set. The user config, the project config and then --config are read in
+// that order, each overriding the keys it sets, and the flags override them all.
+type Config struct {
+	Thresholds ecg.Thresholds `yaml:"thresholds"`
+	Formats    FormatsConfig  `yaml:"formats"`
+	// Gitignore style patterns, relative to the directory being guessed, of files and directories to leave out
+	Ignore []string     `yaml:"ignore"`
+	Output OutputConfig `yaml:"output"`
+}
+
+// FormatsConfig which file formats run, by name
+type FormatsConfig struct {
+	// Only run these, every format if empty
+	Enable []string `yaml:"enable"`
+	// Never run these
+	Disable []string `yaml:"disable"`
+}
+
+// OutputConfig the defaults of the generate output flags
+type OutputConfig struct {
+	Format         string  `yaml:"format"`
+	Confidence     bool    `yaml:"confidence"`
+	MinConfidence  float64 `yaml:"min_confidence"`
+	Subdirectories int     `yaml:"subdirectories"`
+	Nested         bool    `yaml:"nested"`
+}
+
+// Overrides the flags which override the config files, zero values and nil leave the config as it is. The numbers are
+// pointers as 0 is a value they can be set to.
+type Overrides struct {
+	Format         string
+	Confidence     bool
+	MinConfidence  *float64
+	Subdirectories *int
+	Nested         bool
+	// Comma separated name=value pairs, ie "final_newline=0.9,line_length_goal=100"
+	Thresholds string
+	// Comma separated format names
+	Formats        string
+	DisableFormats string
+	// Comma separated patterns, added to those in the config files
+	Ignore string
+}
+
+// DefaultConfig the configuration before any file is read
+func DefaultConfig() *Config {
+	return &Config{
+		Thresholds: ecg.DefaultThresholds(),
+		Output: OutputConfig{
+			Format: "editorconfig",
+		},
+	}
+}
+
+// UserConfigFile the user config, $XDG_CONFIG_HOME/ecguess/config.yaml, empty if there is no config directory
+func UserConfigFile() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "ecguess", "config.yaml")
+}
+
+// LoadConfig reads the user config, the project config in dir and then configFile if it isn't empty, it returns the
+// files read in order. Only configFile has to exist.
+func LoadConfig(dir string, configFile string) (*Config, []string, error) {
+	c := DefaultConfig()
+	var read []string
+	for _, fn := range []string{UserConfigFile(), filepath.Join(dir, ConfigFilename), configFile} {
+		if fn == "" {
+			continue
+		}
+		b, err := os.ReadFile(fn)
+		if errors.Is(err, fs.ErrNotExist) && fn != configFile {
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading config: %w", err)
+		}
+		if err := decodeStrict(b, c); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", fn, err)
+		}
+		read = append(read, fn)
+	}
+	return c, read, nil
+}
+
+// decodeStrict decodes YAML onto v keeping what it doesn't set, keys v doesn't have are errors so typos don't go
+// unnoticed
+func decodeStrict(b []byte, v any) error {
+	d := yaml.NewDecoder(bytes.NewReader(b))
+	d.KnownFields(true)
+	if err := d.Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+// Override applies the flags which were given
+func (c *Config) Override(o Overrides) error {
+	if o.Format != "" {
+		c.Output.Format = o.Format
+	}
+	if o.Confidence {
+		c.Output.Confidence = true
+	}
+	if o.MinConfidence != nil {
+		c.Output.MinConfidence = *o.MinConfidence
+	}
+	if o.Subdirectories != nil {
+		c.Output.Subdirectories = *o.Subdirectories
+	}
+	if o.Nested {
+		c.Output.Nested = true
+	}
+	if o.Thresholds != "" {
+		var b strings.Builder
+		for _, pair := range splitList(o.Thresholds) {
+			name, value, ok := strings.Cut(pair, "=")
+			if !ok {
+				return fmt.Errorf("threshold %s, expected name=value", pair)
+			}
+			fmt.Fprintf(&b, "%s: %s\n", strings.TrimSpace(name), strings.TrimSpace(value))
+		}
+		if err := decodeStrict([]byte(b.String()), &c.Thresholds); err != nil {
+			return fmt.Errorf("thresholds %s: %w", o.Thresholds, err)
+		}
+	}
+	if o.Formats != "" {
+		c.Formats.Enable = splitList(o.Formats)
+	}
+	if o.DisableFormats != "" {
+		c.Formats.Disable = splitList(o.DisableFormats)
+	}
+	c.Ignore = append(c.Ignore, splitList(o.Ignore)...)
+	return nil
+}
+
+// Validate reports the first setting which can't work
+func (c *Config) Validate() error {
+	if err := c.Thresholds.Validate(); err != nil {
+		return err
+	}
+	switch c.Output.Format {
+	case "editorconfig", "json", "yaml":
+	default:
+		return fmt.Errorf("unknown format %s, expected editorconfig, json or yaml", c.Output.Format)
+	}
+	if c.Output.MinConfidence < 0 || c.Output.MinConfidence > 1 {
+		return fmt.Errorf("min_confidence is %v, it has to be between 0 and 1", c.Output.MinConfidence)
+	}
+	known := map[string]bool{}
+	var names []string
+	for _, ff := range ecg.FileFormats() {
+		known[strings.ToLower(ff.Name())] = true
+		names = append(names, ff.Name())
+	}
+	sort.Strings(names)
+	for _, n := range append(append([]string{}, c.Formats.Enable...), c.Formats.Disable...) {
+		if !known[strings.ToLower(n)] {
+			return fmt.Errorf("unknown file format %s, expected one of: %s", n, strings.Join(names, ", "))
+		}
+	}
+	if _, err := c.ignorePatterns(""); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ignorePatterns the Ignore patterns parsed as a .gitignore in dir
+func (c *Config) ignorePatterns(dir string) (gitignore.GitIgnore, error) {
+	var first error
+	ignore := gitignore.New(strings.NewReader(strings.Join(c.Ignore, "\n")), dir, func(e gitignore.Error) bool {
+		if first == nil {
+			first = fmt.Errorf("ignore pattern: %w", e)
+		}
+		return false
+	})
+	return ignore, first
+}
+
+// Options the Analyze options the config sets
+func (c *Config) Options() []ecg.Option {
+	opts := []ecg.Option{ecg.WithThresholds(c.Thresholds)}
+	if len(c.Formats.Enable) > 0 {
+		opts = append(opts, ecg.WithOnlyFormats(c.Formats.Enable...))
+	}
+	if len(c.Formats.Disable) > 0 {
+		opts = append(opts, ecg.WithoutFormats(c.Formats.Disable...))
+	}
+	return opts
+}
+
+// String the config as YAML
+func (c *Config) String() string {
+	b, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Sprintf("# %s\n", err)
+	}
+	return string(b)
+}
+
+// splitList splits a comma separated flag, leaving out empty entries
+func splitList(s string) []string {
+	var result []string
+	for _, e := range strings.Split(s, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// loadConfig the effective config for dir, config errors are usage errors
+func loadConfig(dir string, configFile string, o Overrides) (*Config, error) {
+	c, _, err := LoadConfig(dir, configFile)
+	if err != nil {
+		return nil, usageError("%w", err)
+	}
+	if err := c.Override(o); err != nil {
+		return nil, usageError("%w", err)
+	}
+	if err := c.Validate(); err != nil {
+		return nil, usageError("%w", err)
+	}
+	return c, nil
+}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	user := UserConfigFile()
+	if err := os.MkdirAll(filepath.Dir(user), 0755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(fn, content string) {
+		if err := os.WriteFile(fn, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dir := t.TempDir()
+	write(user, "thresholds:\n  final_newline: 0.9\n  indent_style: 0.7\noutput:\n  format: json\n")
+	write(filepath.Join(dir, ConfigFilename), "thresholds:\n  indent_style: 0.6\nignore:\n  - vendor/\n")
+	c, read, err := LoadConfig(dir, "")
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if len(read) != 2 {
+		t.Errorf("LoadConfig read %v, want the user and project configs", read)
+	}
+	if c.Thresholds.FinalNewline != 0.9 || c.Thresholds.IndentStyle != 0.6 || c.Thresholds.LineEndings != 0.8 {
+		t.Errorf("Thresholds = %+v, want the project to override the user config and the defaults kept", c.Thresholds)
+	}
+	if err := c.Override(Overrides{Format: "yaml", Thresholds: "line_length_goal=100", Ignore: "*.gen.go"}); err != nil {
+		t.Fatalf("Override failed: %v", err)
+	}
+	if c.Output.Format != "yaml" || c.Thresholds.LineLengthGoal != 100 || len(c.Ignore) != 2 {
+		t.Errorf("Override gave %+v", c)
+	}
+	if err := c.Validate(); err != nil {
+		t.Errorf("Validate failed: %v", err)
+	}
+	if err := c.Override(Overrides{Thresholds: "line_goal=100"}); err == nil {
+		t.Error("Override with an unknown threshold succeeded, want an error")
+	}
+	if _, _, err := LoadConfig(dir, filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("LoadConfig with a missing --config succeeded, want an error")
+	}
+	write(filepath.Join(dir, ConfigFilename), "output:\n  formt: json\n")
+	if _, _, err := LoadConfig(dir, ""); err == nil {
+		t.Error("LoadConfig with a misspelt key succeeded, want an error")
+	}
+}
+
+func TestOverride_Zero(t *testing.T) {
+	c := DefaultConfig()
+	c.Output.MinConfidence = 0.8
+	c.Output.Subdirectories = 2
+	minConfidence, subdirectories := 0.0, 0
+	if err := c.Override(Overrides{}); err != nil {
+		t.Fatalf("Override failed: %v", err)
+	}
+	if c.Output.MinConfidence != 0.8 || c.Output.Subdirectories != 2 {
+		t.Errorf("Override without flags gave %+v, want the config kept", c.Output)
+	}
+	if err := c.Override(Overrides{MinConfidence: &minConfidence, Subdirectories: &subdirectories}); err != nil {
+		t.Fatalf("Override failed: %v", err)
+	}
+	if c.Output.MinConfidence != 0 || c.Output.Subdirectories != 0 {
+		t.Errorf("Override with zeros gave %+v, want them to replace the config", c.Output)
+	}
+}
+
+func TestMergeConfidence(t *testing.T) {
+	given := 0.5
+	for _, tt := range []struct {
+		name   string
+		o      Overrides
+		config float64
+		want   float64
+	}{
+		{name: "default", want: MergeMinConfidence},
+		{name: "config below the floor", config: 0.3, want: MergeMinConfidence},
+		{name: "config above the floor", config: 0.9, want: 0.9},
+		{name: "flag", o: Overrides{MinConfidence: &given}, config: 0.9, want: 0.5},
+	} {
+		if got := mergeConfidence(tt.o, OutputConfig{MinConfidence: tt.config}); got != tt.want {
+			t.Errorf("%s: mergeConfidence() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+
+	ecg "editorconfig-guesser"
+)
+
+// Exit codes ecguess returns, keep the readme in step
+const (
+	// ExitFailure anything without a code of its own, ie check finding files which don't conform
+	ExitFailure = 1
+	// ExitUsage bad flags or arguments
+	ExitUsage = 2
+	// ExitAnalysis the directory couldn't be walked or surveyed
+	ExitAnalysis = 3
+	// ExitWrite an .editorconfig or fixed file couldn't be written
+	ExitWrite = 4
+	// ExitNothingToGuess there were no files to guess from
+	ExitNothingToGuess = 5
+	// ExitNoEditorConfig check or diff found no .editorconfig to compare with
+	ExitNoEditorConfig = 6
+)
+
+var (
+	// ErrNothingToGuess there were no files in the directory to guess from
+	ErrNothingToGuess = errors.New("no files to guess from")
+	// ErrNoEditorConfig there was no .editorconfig in the directory to check or diff against
+	ErrNoEditorConfig = errors.New("no " + ecg.EditorConfigFilename + " found")
+)
+
+// ExitError an error ecguess exits with a specific code for
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+// Error ...
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap ...
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode the code ecguess exits with for err, 0 for nil and ExitFailure when err doesn't have one
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var ee *ExitError
+	if errors.As(err, &ee) {
+		return ee.Code
+	}
+	return ExitFailure
+}
+
+func usageError(format string, args ...any) error {
+	return &ExitError{Code: ExitUsage, Err: fmt.Errorf(format, args...)}
+}
+
+func analysisError(err error) error {
+	return &ExitError{Code: ExitAnalysis, Err: err}
+}
+
+func writeError(err error) error {
+	return &ExitError{Code: ExitWrite, Err: err}
+}
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	ecg "editorconfig-guesser"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// progressInterval how often the progress line is redrawn at most
+const progressInterval = 100 * time.Millisecond
+
+// progressLine renders Analyze's progress as a single line which is redrawn in place
+type progressLine struct {
+	w     io.Writer
+	dir   string
+	last  time.Time
+	shown bool
+}
+
+// newProgressLine a progress line on stderr, nil unless stderr is a terminal
+func newProgressLine(dir string) *progressLine {
+	if !isTerminal(os.Stderr) {
+		return nil
+	}
+	return &progressLine{
+		w:   os.Stderr,
+		dir: dir,
+	}
+}
+
+// Update redraws the line, Analyze never calls it concurrently
+func (p *progressLine) Update(progress ecg.Progress) {
+	if time.Since(p.last) < progressInterval {
+		return
+	}
+	p.last = time.Now()
+	p.shown = true
+	_, _ = fmt.Fprintf(p.w, "\r\033[K%s: %s", p.dir, describeProgress(progress))
+}
+
+// Clear removes the line so what follows starts on a clean line
+func (p *progressLine) Clear() {
+	if p == nil || !p.shown {
+		return
+	}
+	p.shown = false
+	_, _ = fmt.Fprint(p.w, "\r\033[K")
+}
+
+// describeProgress ie `120 walked, 100 surveyed, 20 skipped (filtered 15, ignored 5)`
+func describeProgress(p ecg.Progress) string {
+	s := fmt.Sprintf("%d walked, %d surveyed, %d skipped", p.Walked, p.Surveyed, p.SkippedTotal())
+	var reasons []string
+	for reason, n := range p.Skipped {
+		reasons = append(reasons, fmt.Sprintf("%s %d", reason, n))
+	}
+	if len(reasons) == 0 {
+		return s
+	}
+	sort.Strings(reasons)
+	return s + " (" + strings.Join(reasons, ", ") + ")"
+}
+
+// isTerminal true if f is a character device such as a terminal
+func isTerminal(f *os.File) bool {
+	st, err := f.Stat()
+	return err == nil && st.Mode()&os.ModeCharDevice != 0
+}
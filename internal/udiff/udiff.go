@@ -0,0 +1,263 @@
+// Package udiff produces unified diffs of text files.
+package udiff
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Context the number of unchanged lines shown around each change
+const Context = 3
+
+type opKind int
+
+const (
+	equal opKind = iota
+	deletion
+	insertion
+)
+
+type op struct {
+	kind opKind
+	// Index of the line in a (equal, deletion) or b (insertion)
+	a, b int
+}
+
+// Unified returns a unified diff turning a into b, an empty string when they are the same
+func Unified(fromName, toName string, a, b []byte) string {
+	if bytes.Equal(a, b) {
+		return ""
+	}
+	al := splitLines(a)
+	bl := splitLines(b)
+	ops := diff(al, bl)
+	sb := &strings.Builder{}
+	_, _ = fmt.Fprintf(sb, "--- %s\n+++ %s\n", fromName, toName)
+	for start := 0; start < len(ops); {
+		// Find the next change
+		for start < len(ops) && ops[start].kind == equal {
+			start++
+		}
+		if start >= len(ops) {
+			break
+		}
+		// Extend the hunk until there is more than twice the context of unchanged lines
+		end := start
+		for i := start; i < len(ops); i++ {
+			if ops[i].kind != equal {
+				end = i + 1
+			} else if i-end >= 2*Context {
+				break
+			}
+		}
+		hunkStart := max(start-Context, 0)
+		hunkEnd := min(end+Context, len(ops))
+		writeHunk(sb, ops[hunkStart:hunkEnd], al, bl)
+		start = hunkEnd
+	}
+	return sb.String()
+}
+
+func writeHunk(sb *strings.Builder, ops []op, al, bl []string) {
+	aStart, bStart := -1, -1
+	aCount, bCount := 0, 0
+	for _, o := range ops {
+		switch o.kind {
+		case equal:
+			if aStart < 0 {
+				aStart = o.a
+			}
+			if bStart < 0 {
+				bStart = o.b
+			}
+			aCount++
+			bCount++
+		case deletion:
+			if aStart < 0 {
+				aStart = o.a
+			}
+			aCount++
+		case insertion:
+			if bStart < 0 {
+				bStart = o.b
+			}
+			bCount++
+		}
+	}
+	// Empty ranges refer to the line before, per the format
+	if aStart < 0 {
+		aStart = ops[0].a - 1
+	}
+	if bStart < 0 {
+		bStart = ops[0].b - 1
+	}
+	_, _ = fmt.Fprintf(sb, "@@ -%s +%s @@\n", hunkRange(aStart, aCount), hunkRange(bStart, bCount))
+	for _, o := range ops {
+		switch o.kind {
+		case equal:
+			writeLine(sb, " ", al[o.a])
+		case deletion:
+			writeLine(sb, "-", al[o.a])
+		case insertion:
+			writeLine(sb, "+", bl[o.b])
+		}
+	}
+}
+
+func hunkRange(start, count int) string {
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+func writeLine(sb *strings.Builder, prefix, line string) {
+	sb.WriteString(prefix)
+	sb.WriteString(line)
+	if !strings.HasSuffix(line, "\n") {
+		sb.WriteString("\n\\ No newline at end of file\n")
+	}
+}
+
+// splitLines splits after each new line, keeping them so line ending changes show up
+func splitLines(b []byte) []string {
+	if len(b) == 0 {
+		return nil
+	}
+	lines := strings.SplitAfter(string(b), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// maxBisect the most edits bisect looks through before giving up on the range, so wildly different inputs are shown
+// as the one replacement rather than taking quadratic time
+const maxBisect = 1 << 12
+
+// differ builds the edit script of a and b in order
+type differ struct {
+	a, b []string
+	ops  []op
+}
+
+// diff the shortest edit script from a to b using Myers' algorithm in linear space, see bisect
+func diff(a, b []string) []op {
+	d := &differ{a: a, b: b}
+	d.compare(0, len(a), 0, len(b))
+	return d.ops
+}
+
+// compare adds the edits turning a[aLo:aHi] into b[bLo:bHi]
+func (d *differ) compare(aLo, aHi, bLo, bHi int) {
+	for aLo < aHi && bLo < bHi && d.a[aLo] == d.b[bLo] {
+		d.ops = append(d.ops, op{kind: equal, a: aLo, b: bLo})
+		aLo++
+		bLo++
+	}
+	suffix := 0
+	for aLo < aHi-suffix && bLo < bHi-suffix && d.a[aHi-suffix-1] == d.b[bHi-suffix-1] {
+		suffix++
+	}
+	aHi -= suffix
+	bHi -= suffix
+	if x, y, ok := d.bisect(aLo, aHi, bLo, bHi); ok {
+		d.compare(aLo, x, bLo, y)
+		d.compare(x, aHi, y, bHi)
+	} else {
+		for x := aLo; x < aHi; x++ {
+			d.ops = append(d.ops, op{kind: deletion, a: x, b: bLo})
+		}
+		for y := bLo; y < bHi; y++ {
+			d.ops = append(d.ops, op{kind: insertion, a: aHi, b: y})
+		}
+	}
+	for i := 0; i < suffix; i++ {
+		d.ops = append(d.ops, op{kind: equal, a: aHi + i, b: bHi + i})
+	}
+}
+
+// bisect finds where the shortest edit script of the ranges crosses its middle by searching forwards from the start
+// and backwards from the end at once. False if either range is empty or there are more than maxBisect edits, the
+// ranges are then best replaced outright.
+func (d *differ) bisect(aLo, aHi, bLo, bHi int) (int, int, bool) {
+	n, m := aHi-aLo, bHi-bLo
+	if n == 0 || m == 0 {
+		return 0, 0, false
+	}
+	maxD := min((n+m+1)/2, maxBisect)
+	offset := maxD
+	// The furthest x reached on each diagonal k, forwards and backwards, -1 where none has been
+	vf := make([]int, 2*maxD+2)
+	vb := make([]int, 2*maxD+2)
+	for i := range vf {
+		vf[i] = -1
+		vb[i] = -1
+	}
+	vf[offset+1] = 0
+	vb[offset+1] = 0
+	delta := n - m
+	// With an odd delta the paths meet going forwards, otherwise going backwards
+	front := delta%2 != 0
+	// Diagonals which have run off the edge are skipped
+	kfStart, kfEnd, kbStart, kbEnd := 0, 0, 0, 0
+	for e := 0; e < maxD; e++ {
+		for k := -e + kfStart; k <= e-kfEnd; k += 2 {
+			var x int
+			if k == -e || (k != e && vf[offset+k-1] < vf[offset+k+1]) {
+				x = vf[offset+k+1]
+			} else {
+				x = vf[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && d.a[aLo+x] == d.b[bLo+y] {
+				x++
+				y++
+			}
+			vf[offset+k] = x
+			switch {
+			case x > n:
+				kfEnd += 2
+			case y > m:
+				kfStart += 2
+			case front:
+				if kb := offset + delta - k; kb >= 0 && kb < len(vb) && vb[kb] != -1 && x >= n-vb[kb] {
+					return aLo + x, bLo + y, true
+				}
+			}
+		}
+		for k := -e + kbStart; k <= e-kbEnd; k += 2 {
+			var x int
+			if k == -e || (k != e && vb[offset+k-1] < vb[offset+k+1]) {
+				x = vb[offset+k+1]
+			} else {
+				x = vb[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && d.a[aHi-x-1] == d.b[bHi-y-1] {
+				x++
+				y++
+			}
+			vb[offset+k] = x
+			switch {
+			case x > n:
+				kbEnd += 2
+			case y > m:
+				kbStart += 2
+			case !front:
+				if kf := offset + delta - k; kf >= 0 && kf < len(vf) && vf[kf] != -1 {
+					fx := vf[kf]
+					fy := offset + fx - kf
+					if fx >= n-x {
+						return aLo + fx, bLo + fy, true
+					}
+				}
+			}
+		}
+	}
+	return 0, 0, false
+}
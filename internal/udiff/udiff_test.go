@@ -0,0 +1,129 @@
+package udiff
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{name: "Same", a: "a\nb\n", b: "a\nb\n", want: ""},
+		{name: "Line ending", a: "a\r\nb\r\n", b: "a\nb\n", want: "--- a\n+++ b\n@@ -1,2 +1,2 @@\n-a\r\n-b\r\n+a\n+b\n"},
+		{name: "Final new line", a: "a\nb", b: "a\nb\n", want: "--- a\n+++ b\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n"},
+		{name: "Empty to something", a: "", b: "a\n", want: "--- a\n+++ b\n@@ -0,0 +1 @@\n+a\n"},
+		{
+			name: "Separate hunks",
+			a:    "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n",
+			b:    "1\nX\n3\n4\n5\n6\n7\n8\n9\n10\n11\nY\n",
+			want: "--- a\n+++ b\n@@ -1,5 +1,5 @@\n 1\n-2\n+X\n 3\n 4\n 5\n@@ -9,4 +9,4 @@\n 9\n 10\n 11\n-12\n+Y\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, Unified("a", "b", []byte(tt.a), []byte(tt.b))); diff != "" {
+				t.Errorf("Unified() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// apply rebuilds both sides from the edit script, checking it is in order
+func apply(t *testing.T, ops []op, a, b []string) ([]string, []string) {
+	t.Helper()
+	var ga, gb []string
+	for _, o := range ops {
+		switch o.kind {
+		case equal:
+			if a[o.a] != b[o.b] {
+				t.Fatalf("equal op %+v joins %q and %q", o, a[o.a], b[o.b])
+			}
+			ga = append(ga, a[o.a])
+			gb = append(gb, b[o.b])
+		case deletion:
+			ga = append(ga, a[o.a])
+		case insertion:
+			gb = append(gb, b[o.b])
+		}
+	}
+	return ga, gb
+}
+
+func TestDiff(t *testing.T) {
+	numbered := func(n int, f func(i int) string) []string {
+		lines := make([]string, n)
+		for i := range lines {
+			lines[i] = f(i)
+		}
+		return lines
+	}
+	tests := []struct {
+		name  string
+		a, b  []string
+		edits int
+	}{
+		{name: "Interleaved", a: strings.Split("abcabba", ""), b: strings.Split("cbabac", ""), edits: 5},
+		{name: "Prefix and suffix", a: strings.Split("xxaxx", ""), b: strings.Split("xxbxx", ""), edits: 2},
+		{
+			name:  "Every other line",
+			a:     numbered(2000, func(i int) string { return fmt.Sprintf("%d\n", i) }),
+			b:     numbered(2000, func(i int) string { return fmt.Sprintf("%d%s\n", i, strings.Repeat("!", i%2)) }),
+			edits: 2000,
+		},
+		{
+			// More edits than bisect looks through, replaced outright
+			name:  "Unrelated",
+			a:     numbered(3*maxBisect, func(i int) string { return fmt.Sprintf("a%d\n", i) }),
+			b:     numbered(3*maxBisect, func(i int) string { return fmt.Sprintf("b%d\n", i) }),
+			edits: 6 * maxBisect,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ops := diff(tt.a, tt.b)
+			ga, gb := apply(t, ops, tt.a, tt.b)
+			if diff := cmp.Diff(tt.a, ga); diff != "" {
+				t.Errorf("a mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.b, gb); diff != "" {
+				t.Errorf("b mismatch (-want +got):\n%s", diff)
+			}
+			edits := 0
+			for _, o := range ops {
+				if o.kind != equal {
+					edits++
+				}
+			}
+			if edits != tt.edits {
+				t.Errorf("%d edits, want %d", edits, tt.edits)
+			}
+		})
+	}
+}
+
+func TestUnified_Large(t *testing.T) {
+	// Rewriting a big CRLF file to LF changes every line, which once took hundreds of megabytes
+	crlf := &strings.Builder{}
+	for i := 0; i < 3000; i++ {
+		_, _ = fmt.Fprintf(crlf, "line %d\r\n", i)
+	}
+	a := []byte(crlf.String())
+	b := []byte(strings.ReplaceAll(crlf.String(), "\r\n", "\n"))
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	got := Unified("a", "b", a, b)
+	runtime.ReadMemStats(&after)
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 32<<20 {
+		t.Errorf("Unified() allocated %d MB", allocated>>20)
+	}
+	if removed, added := strings.Count(got, "\n-line"), strings.Count(got, "\n+line"); removed != 3000 || added != 3000 {
+		t.Errorf("Unified() removed %d and added %d lines, want 3000 each", removed, added)
+	}
+}
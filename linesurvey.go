@@ -1,7 +1,10 @@
 // Package ecg guesses the editorconfig settings of a project.
 package ecg
 
-import "bytes"
+import (
+	"strings"
+	"unicode/utf8"
+)
 
 // LineLengthDetail ...
 type LineLengthDetail struct{ length, tabIndentation int }
@@ -13,12 +16,53 @@ type LineSurvey struct {
 	WhitespaceSuffix map[string]int
 	WindowNewlines   int
 	LineLengths      map[LineLengthDetail]int
+	// Runes outside of ASCII
+	NonASCII int
 }
 
 // TrailingWhitespaceCommon ...
 func (survey *LineSurvey) TrailingWhitespaceCommon() bool {
+	return survey.trailingWhitespaceCommon(DefaultThresholds().TrailingWhitespaceCommon)
+}
+
+// trailingWhitespaceCommon true if the file has more than limit distinct trailing whitespace suffixes
+func (survey *LineSurvey) trailingWhitespaceCommon(limit int) bool {
 	// TODO this is too simplistic -- I don't know what circumstance this would be for.... Request PR / case study
-	return len(survey.WhitespaceSuffix) > 5
+	return len(survey.WhitespaceSuffix) > limit
+}
+
+// TrailingWhitespaceLines the number of lines which end in whitespace
+func (survey *LineSurvey) TrailingWhitespaceLines() int {
+	count := 0
+	for k, v := range survey.WhitespaceSuffix {
+		// The suffix of an empty line runs back to the end of the previous line
+		if k[strings.LastIndexAny(k, "\r\n")+1:] != "" {
+			count += v
+		}
+	}
+	return count
+}
+
+// IndentedWith the number of lines whose indentation starts with r, ie ' ' or '\t'
+func (survey *LineSurvey) IndentedWith(r rune) int {
+	count := 0
+	for k, v := range survey.WhitespacePrefix {
+		if strings.HasPrefix(k, string(r)) {
+			count += v
+		}
+	}
+	return count
+}
+
+// LinesLongerThan the number of lines wider than maximum when tabs used for indentation are tabWidth wide
+func (survey *LineSurvey) LinesLongerThan(maximum, tabWidth int) int {
+	count := 0
+	for k, v := range survey.LineLengths {
+		if k.length-k.tabIndentation+k.tabIndentation*tabWidth > maximum {
+			count += v
+		}
+	}
+	return count
 }
 
 // LinuxNewlinesPercent ...
@@ -39,76 +83,160 @@ func (survey *LineSurvey) WindowNewlinesPercent() float64 {
 
 // LineSurveySample ...
 func LineSurveySample(b []byte) *LineSurvey {
-	ls := &LineSurvey{
-		NewLines:         0,
-		WhitespacePrefix: map[string]int{},
-		WhitespaceSuffix: map[string]int{},
-		LineLengths:      map[LineLengthDetail]int{},
+	ls := NewLineSurveyor()
+	_, _ = ls.Write(b)
+	return ls.Survey()
+}
+
+// maxWhitespaceRun the most whitespace runes kept for a single prefix or suffix, longer runs keep their end so memory
+// stays bounded on pathological input
+const maxWhitespaceRun = 4096
+
+// LineSurveyor builds a LineSurvey from chunks of a file as they are read, lines and runes may be split across chunks
+type LineSurveyor struct {
+	ls *LineSurvey
+	// Index of the next rune
+	i            int
+	lineTabCount int
+	lastLF       int
+	lastNWS      int
+	lastCR       int
+	// Runes since the last non whitespace rune
+	whitespace []rune
+	// Bytes of a rune split across chunks
+	partial []byte
+}
+
+// NewLineSurveyor ...
+func NewLineSurveyor() *LineSurveyor {
+	return &LineSurveyor{
+		ls: &LineSurvey{
+			NewLines:         0,
+			WhitespacePrefix: map[string]int{},
+			WhitespaceSuffix: map[string]int{},
+			LineLengths:      map[LineLengthDetail]int{},
+		},
+		lastLF:  -1,
+		lastNWS: -1,
+		lastCR:  -1,
 	}
-	lineLength := 0
-	lineTabCount := 0
-	lastLF := -1
-	lastNWS := -1
-	lastCR := -1
-	rns := bytes.Runes(b)
-	for i, r := range rns {
-		lineLength++
-		switch r {
-		case '\n':
-			lineLength = 0
-			ls.NewLines++
-			end := i
-			if lastCR == i-1 {
-				ls.WindowNewlines++
-				end = lastCR
-			}
-			start := lastNWS + 1
-			if start < 0 {
-				start = 0
-			}
-			if end < start {
-				end = start
-			}
-			suffix := string(rns[start:end])
-			ls.WhitespaceSuffix[suffix] = ls.WhitespaceSuffix[suffix] + 1
-			count := lineTabCount
-			if count == -1 {
-				count = 0
-			}
-			ls.LineLengths[LineLengthDetail{length: (end) - (lastLF + 1), tabIndentation: count}]++
-			lineTabCount = 0
-			lastLF = i
-		case '\t':
-			if lastNWS <= lastLF {
-				lineTabCount++
-			}
-		case '\r':
-			lastCR = i
-			if lastNWS <= lastLF {
-				lineTabCount = 0
-			}
-		case ' ':
-			if lastNWS <= lastLF {
-				lineTabCount = 0
-			}
-		default:
-			if lastNWS <= lastLF {
-				start := lastLF + 1
-				if start < 0 {
-					start = 0
-				}
-				end := i
-				if end < start {
-					end = start
-				}
-				if lineTabCount != end-start {
-					lineTabCount = -1
+}
+
+// Write surveys the next chunk, it never fails
+func (s *LineSurveyor) Write(b []byte) (int, error) {
+	n := len(b)
+	if len(s.partial) > 0 {
+		b = append(s.partial, b...)
+		s.partial = nil
+	}
+	for len(b) > 0 {
+		if !utf8.FullRune(b) {
+			s.partial = append([]byte(nil), b...)
+			break
+		}
+		r, size := utf8.DecodeRune(b)
+		b = b[size:]
+		s.rune(r)
+	}
+	return n, nil
+}
+
+// Break starts over at a new line without counting one, for when the chunks which follow aren't contiguous with those
+// before
+func (s *LineSurveyor) Break() {
+	s.flushPartial()
+	s.lineTabCount = 0
+	s.lastLF = s.i - 1
+	s.lastNWS = s.i - 1
+	s.lastCR = -1
+	s.whitespace = s.whitespace[:0]
+}
+
+// Survey the survey of everything written so far, a rune split at the end is counted as invalid
+func (s *LineSurveyor) Survey() *LineSurvey {
+	s.flushPartial()
+	return s.ls
+}
+
+func (s *LineSurveyor) flushPartial() {
+	for _, c := range s.partial {
+		s.rune(rune(c))
+	}
+	s.partial = nil
+}
+
+func (s *LineSurveyor) rune(r rune) {
+	if r == '\uFEFF' && s.i == 0 {
+		// A byte order mark isn't part of the first line
+		return
+	}
+	i := s.i
+	s.i++
+	ls := s.ls
+	switch r {
+	case '\n':
+		ls.NewLines++
+		end := i
+		suffix := s.whitespace
+		if s.lastCR == i-1 {
+			ls.WindowNewlines++
+			end = s.lastCR
+			suffix = suffix[:len(suffix)-1]
+		}
+		if end < s.lastNWS+1 {
+			end = s.lastNWS + 1
+		}
+		ls.WhitespaceSuffix[string(suffix)]++
+		count := s.lineTabCount
+		if count == -1 {
+			count = 0
+		}
+		ls.LineLengths[LineLengthDetail{length: end - (s.lastLF + 1), tabIndentation: count}]++
+		s.lineTabCount = 0
+		s.lastLF = i
+		s.addWhitespace(r)
+	case '\t':
+		if s.lastNWS <= s.lastLF {
+			s.lineTabCount++
+		}
+		s.addWhitespace(r)
+	case '\r':
+		s.lastCR = i
+		if s.lastNWS <= s.lastLF {
+			s.lineTabCount = 0
+		}
+		s.addWhitespace(r)
+	case ' ':
+		if s.lastNWS <= s.lastLF {
+			s.lineTabCount = 0
+		}
+		s.addWhitespace(r)
+	default:
+		if r > 127 {
+			ls.NonASCII++
+		}
+		if s.lastNWS <= s.lastLF {
+			// The indentation is whatever whitespace came after the last new line
+			prefix := s.whitespace
+			for j := len(prefix) - 1; j >= 0; j-- {
+				if prefix[j] == '\n' {
+					prefix = prefix[j+1:]
+					break
 				}
-				prefix := string(rns[start:end])
-				ls.WhitespacePrefix[prefix] = ls.WhitespacePrefix[prefix] + 1
 			}
-			lastNWS = i
+			if s.lineTabCount != len(prefix) {
+				s.lineTabCount = -1
+			}
+			ls.WhitespacePrefix[string(prefix)]++
 		}
+		s.lastNWS = i
+		s.whitespace = s.whitespace[:0]
+	}
+}
+
+func (s *LineSurveyor) addWhitespace(r rune) {
+	if len(s.whitespace) >= maxWhitespaceRun {
+		s.whitespace = append(s.whitespace[:0], s.whitespace[len(s.whitespace)-maxWhitespaceRun/2:]...)
 	}
-	return ls
+	s.whitespace = append(s.whitespace, r)
 }
@@ -190,3 +190,22 @@ func TestLineSurveySample(t *testing.T) {
 		})
 	}
 }
+
+func TestLineSurveyor_Chunks(t *testing.T) {
+	for _, s := range []string{
+		"one\r\nword\r\nper\nline",
+		"\t  \ttoken  \r\n\n  \n    héllo wörld\t\n",
+		"\tприве́т\r\n  日本語 \n",
+		"bad \xff\xfe utf8\n\xe6\x97",
+	} {
+		want := LineSurveySample([]byte(s))
+		for i := 0; i <= len(s); i++ {
+			ls := NewLineSurveyor()
+			_, _ = ls.Write([]byte(s[:i]))
+			_, _ = ls.Write([]byte(s[i:]))
+			if diff := cmp.Diff(want, ls.Survey()); diff != "" {
+				t.Errorf("%q split at %d mismatch (-want +got):\n%s", s, i, diff)
+			}
+		}
+	}
+}
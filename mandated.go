@@ -0,0 +1,159 @@
+package ecg
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+var (
+	// SurveyedTemplate renders a BasicSurveyor's properties, for the formats which don't need a template of their own
+	//go:embed "surveyed.ectemplate"
+	SurveyedTemplate []byte
+)
+
+// Mandate a property the language requires whatever its files look like, ie gofmt's `indent_style = tab`
+type Mandate struct {
+	Key   string
+	Value string
+	// Why the language requires it, given in the warnings about the files which contradict it
+	Reason string
+}
+
+// NewMandatedPresence a Presence which also surveys its files. The mandates are always in the section, followed by
+// whatever the template, SurveyedTemplate if nil, makes of what the survey found beyond `[*]`, less the properties the
+// mandates already set.
+// The files which contradict a mandate, ie `.go` files indented with spaces that were never gofmt'ed, are the
+// surveyor's Dissents and each one is a Warning.
+func NewMandatedPresence(name string, globs []string, filetypes []string, mandates []Mandate, ectemplate []byte) FileFormat {
+	if ectemplate == nil {
+		ectemplate = SurveyedTemplate
+	}
+	surveyor := NewBasicSurveyor()
+	for _, m := range mandates {
+		surveyor.Mandate(m.Key, m.Value)
+	}
+	return NewContainer(name, &MandatedPresence{
+		Presence: Presence{
+			globs:      globs,
+			filetypes:  filetypes,
+			ectemplate: ectemplate,
+		},
+		mandates: mandates,
+		surveyor: surveyor,
+	})
+}
+
+// MandatedPresence see NewMandatedPresence
+type MandatedPresence struct {
+	Presence
+	mandates          []Mandate
+	surveyor          *BasicSurveyor
+	everyFileSurveyor *BasicSurveyor
+}
+
+// SetBasicSurveyor ...
+func (l *MandatedPresence) SetBasicSurveyor(af *BasicSurveyor) {
+	l.everyFileSurveyor = af
+}
+
+// SetThresholds ...
+func (l *MandatedPresence) SetThresholds(t Thresholds) {
+	l.surveyor.SetThresholds(t)
+}
+
+// RunFile ...
+func (l *MandatedPresence) RunFile(f *File) ([]*SummaryResult, error) {
+	if _, err := l.Presence.RunFile(f); err != nil {
+		return nil, err
+	}
+	if !l.Claims(f) {
+		return nil, nil
+	}
+	if _, _, _, err := l.surveyor.ReadFile(f); err != nil {
+		return nil, fmt.Errorf("running: %w", err)
+	}
+	return nil, nil
+}
+
+// End ...
+func (l *MandatedPresence) End() ([]*SummaryResult, error) {
+	summary, err := l.Presence.End()
+	if err != nil || len(summary) == 0 {
+		return summary, err
+	}
+	l.surveyor.Summarize()
+	summary[0].Confidence = l.surveyor.OverallConfidence()
+	summary[0].Surveyor = l.surveyor
+	summary[0].Template = &mandatedTemplate{
+		ectemplate:        l.ectemplate,
+		mandates:          l.mandates,
+		surveyor:          l.surveyor,
+		everyFileSurveyor: l.everyFileSurveyor,
+	}
+	summary[0].Warnings = l.contradictions()
+	return summary, nil
+}
+
+// contradictions a Warning for each file which contradicts a mandate
+func (l *MandatedPresence) contradictions() []*Warning {
+	reasons := map[string]string{}
+	for _, m := range l.mandates {
+		reasons[m.Key] = m.Reason
+		if m.Reason == "" {
+			reasons[m.Key] = (&Property{Key: m.Key, Value: m.Value}).String() + " is mandated"
+		}
+	}
+	var result []*Warning
+	for _, d := range l.surveyor.Dissents() {
+		reason, ok := reasons[d.Property]
+		if !ok {
+			continue
+		}
+		result = append(result, &Warning{
+			Filename: d.Filename,
+			Message:  describeVote(d.Property, d.Value, false) + " but " + reason,
+		})
+	}
+	return result
+}
+
+// mandatedTemplate renders a MandatedPresence's section
+type mandatedTemplate struct {
+	ectemplate        []byte
+	mandates          []Mandate
+	surveyor          *BasicSurveyor
+	everyFileSurveyor *BasicSurveyor
+}
+
+// String ...
+func (t *mandatedTemplate) String() (string, error) {
+	sb := &strings.Builder{}
+	mandated := map[string]bool{}
+	for _, m := range t.mandates {
+		mandated[m.Key] = true
+		sb.WriteString((&Property{Key: m.Key, Value: m.Value}).String())
+		sb.WriteString("\n")
+	}
+	b := bytes.NewBuffer(nil)
+	tmpl, err := template.New("").Parse(string(t.ectemplate))
+	if err != nil {
+		return "", err
+	}
+	if err := tmpl.Execute(b, t.surveyor.Delta(t.everyFileSurveyor)); err != nil {
+		return "", err
+	}
+	for _, line := range strings.SplitAfter(b.String(), "\n") {
+		if p := ParseProperty(line); p.Key != "" && mandated[p.Key] {
+			continue
+		}
+		sb.WriteString(line)
+	}
+	return sb.String(), nil
+}
+
+var _ FileClaimer = (*MandatedPresence)(nil)
+var _ BasicSurveyorSetter = (*MandatedPresence)(nil)
+var _ ThresholdsSetter = (*MandatedPresence)(nil)
@@ -0,0 +1,64 @@
+package ecg_test
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	ecg "editorconfig-guesser"
+	_ "editorconfig-guesser/fileformats"
+)
+
+func TestMandatedPresence(t *testing.T) {
+	spaced := &fstest.MapFile{Data: []byte("package main\n\nfunc main() {\n    println()\n}\n")}
+	mapFS := fstest.MapFS{
+		"main.go": &fstest.MapFile{Data: []byte("package main\n\nfunc main() {\n\tprintln()\n}\n")},
+		"a.go":    spaced,
+		"b.go":    spaced,
+		"a.md":    &fstest.MapFile{Data: []byte("# A\n\nText")},
+		"b.md":    &fstest.MapFile{Data: []byte("# B\n\nText")},
+		"c.md":    &fstest.MapFile{Data: []byte("# C\n\nText")},
+	}
+	guess, err := ecg.Analyze(mapFS, func(f *ecg.File) bool { return false })
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	s := guess.Section("*.go")
+	if s == nil {
+		t.Fatalf("no [*.go] section in:\n%s", guess)
+	}
+	p := s.Get("indent_style")
+	if p == nil || p.Value != "tab" || p.Confidence != 1 {
+		t.Fatalf("indent_style = %+v, want a certain tab", p)
+	}
+	if p.Comment != "" {
+		t.Errorf("indent_style comment = %q, want none", p.Comment)
+	}
+	if p := s.Get("end_of_line"); p != nil {
+		t.Errorf("end_of_line = %+v, want it left to [*]", p)
+	}
+	if p := s.Get("insert_final_newline"); p == nil || p.Value != "true" {
+		t.Errorf("insert_final_newline = %+v, want true where [*] can't tell", p)
+	}
+	if strings.Count(s.String(), "indent_style") != 1 {
+		t.Errorf("indent_style is in the section more than once:\n%s", s)
+	}
+	var dissents []string
+	for _, d := range s.Surveyor.Dissents() {
+		if d.Property == "indent_style" {
+			dissents = append(dissents, d.Filename+" "+d.Value+" "+d.Majority)
+		}
+	}
+	if got, want := strings.Join(dissents, ", "), "a.go space tab, b.go space tab"; got != want {
+		t.Errorf("Dissents() = %q, want %q", got, want)
+	}
+	var warnings []string
+	for _, w := range guess.Warnings {
+		warnings = append(warnings, w.String())
+	}
+	want := "a.go: Go: indents with spaces but gofmt indents with tabs\n" +
+		"b.go: Go: indents with spaces but gofmt indents with tabs"
+	if got := strings.Join(warnings, "\n"); got != want {
+		t.Errorf("Warnings = %q, want %q", got, want)
+	}
+}
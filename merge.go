@@ -0,0 +1,109 @@
+package ecg
+
+import (
+	"fmt"
+)
+
+// Change a property Merge added or updated
+type Change struct {
+	// Header of the section, ie `*.go`
+	Section  string
+	Property string
+	// The previous value, empty if the property was added
+	From string
+	To   string
+}
+
+// String ...
+func (c *Change) String() string {
+	if c.From == "" {
+		return fmt.Sprintf("[%s] %s: added %s", c.Section, c.Property, c.To)
+	}
+	return fmt.Sprintf("[%s] %s: %s -> %s", c.Section, c.Property, c.From, c.To)
+}
+
+// Merge adds or updates the properties of the guess with a confidence of at least minConfidence in existing, which is
+// modified in place. Sections are matched by their header, updated properties keep their position but take the guess's
+// comment in place of their own, added properties go after the last property of the section and new sections keep
+// their place relative to the guess.
+// Everything else, ie comments, unknown sections and properties such as `ij_*`, is left alone.
+func Merge(existing, guess *Guess, minConfidence float64) []*Change {
+	var changes []*Change
+	// Where the next new section goes, after the last guessed section which exists
+	insertAt := 0
+	for _, gs := range guess.Sections {
+		var confident []*Property
+		for _, p := range gs.Properties {
+			if p.Key != "" && p.Confidence >= minConfidence {
+				confident = append(confident, p)
+			}
+		}
+		header := gs.Header()
+		es := existing.Section(header)
+		if es == nil {
+			if len(confident) == 0 {
+				continue
+			}
+			es = &Section{
+				Format:     gs.Format,
+				FileGlobs:  gs.FileGlobs,
+				Path:       gs.Path,
+				Confidence: gs.Confidence,
+				Surveyor:   gs.Surveyor,
+				Result:     gs.Result,
+			}
+			if insertAt > 0 {
+				if previous := existing.Sections[insertAt-1]; len(previous.Properties) == 0 || !previous.Properties[len(previous.Properties)-1].IsBlank() {
+					previous.Properties = append(previous.Properties, &Property{})
+				}
+			}
+			existing.Sections = append(existing.Sections[:insertAt], append([]*Section{es}, existing.Sections[insertAt:]...)...)
+			for _, p := range confident {
+				es.Properties = append(es.Properties, mergedProperty(p))
+				changes = append(changes, &Change{Section: header, Property: p.Key, To: normalizeValue(p.Value)})
+			}
+			es.Properties = append(es.Properties, &Property{})
+			insertAt++
+			continue
+		}
+		for i, s := range existing.Sections {
+			if s == es && i+1 > insertAt {
+				insertAt = i + 1
+			}
+		}
+		for _, p := range confident {
+			if ep := es.Get(p.Key); ep != nil {
+				if normalizeValue(ep.Value) == normalizeValue(p.Value) {
+					continue
+				}
+				changes = append(changes, &Change{Section: header, Property: p.Key, From: ep.Value, To: normalizeValue(p.Value)})
+				ep.Value = normalizeValue(p.Value)
+				// Whatever the old comment said was about the old value
+				ep.Comment = p.Comment
+				continue
+			}
+			es.Properties = insertProperty(es.Properties, mergedProperty(p))
+			changes = append(changes, &Change{Section: header, Property: p.Key, To: normalizeValue(p.Value)})
+		}
+	}
+	return changes
+}
+
+// mergedProperty a copy of a guessed property with a value the editorconfig specification accepts
+func mergedProperty(p *Property) *Property {
+	return &Property{
+		Key:        p.Key,
+		Value:      normalizeValue(p.Value),
+		Comment:    p.Comment,
+		Confidence: p.Confidence,
+	}
+}
+
+// insertProperty inserts p after the last property, before any trailing comments and blank lines
+func insertProperty(properties []*Property, p *Property) []*Property {
+	i := len(properties)
+	for i > 0 && properties[i-1].Key == "" {
+		i--
+	}
+	return append(properties[:i], append([]*Property{p}, properties[i:]...)...)
+}
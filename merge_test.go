@@ -0,0 +1,83 @@
+package ecg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMerge(t *testing.T) {
+	existing, err := ParseEditorConfig(strings.NewReader(`# Hand written
+root = true
+
+[*.kt]
+indent_size = 2 # house style
+ij_kotlin_allow_trailing_comma = true
+; end of kotlin
+
+[*.md]
+trim_trailing_whitespace = false
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	guess := &Guess{
+		Sections: []*Section{
+			{FileGlobs: []string{"*"}, Properties: []*Property{
+				{Key: "end_of_line", Value: "lf", Confidence: 0.9},
+				{Key: "charset", Value: "utf-8", Confidence: 0.2},
+				{},
+			}},
+			{FileGlobs: []string{"*.kt"}, Properties: []*Property{
+				{Key: "indent_style", Value: "spaces", Confidence: 0.95},
+				{Key: "indent_size", Value: "4", Confidence: 0.9, Comment: "90% of *.kt"},
+				{Key: "max_line_length", Value: "120", Confidence: 0.1},
+				{},
+			}},
+			{FileGlobs: []string{"*.go"}, Properties: []*Property{
+				{Key: "indent_style", Value: "tab", Confidence: 0.3},
+				{},
+			}},
+			{FileGlobs: []string{"*.yaml"}, Properties: []*Property{
+				{Key: "indent_size", Value: "2", Confidence: 0.8},
+				{},
+			}},
+		},
+	}
+	changes := Merge(existing, guess, 0.5)
+	var got []string
+	for _, c := range changes {
+		got = append(got, c.String())
+	}
+	want := []string{
+		"[*] end_of_line: added lf",
+		"[*.kt] indent_style: added space",
+		"[*.kt] indent_size: 2 -> 4",
+		"[*.yaml] indent_size: added 2",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Merge() changes mismatch (-want +got):\n%s", diff)
+	}
+	wantFile := `# Hand written
+root = true
+
+[*]
+end_of_line = lf
+
+[*.kt]
+indent_size = 4 # 90% of *.kt
+ij_kotlin_allow_trailing_comma = true
+indent_style = space
+; end of kotlin
+
+[*.yaml]
+indent_size = 2
+
+[*.md]
+trim_trailing_whitespace = false
+`
+	if diff := cmp.Diff(wantFile, existing.String()); diff != "" {
+		t.Errorf("Merge() file mismatch (-want +got):\n%s", diff)
+	}
+}
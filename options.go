@@ -0,0 +1,164 @@
+package ecg
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Option configures Analyze, LoadEditorConfigs, Check and Fixes, options which don't apply are ignored
+type Option func(*analyzeOptions)
+
+type analyzeOptions struct {
+	subdirectoryDepth    int
+	subdirectoryMinFiles int
+	skipConsolidation    bool
+	sampling             SamplePolicy
+	jobs                 int
+	walkFilter           WalkFilter
+	readTimeout          time.Duration
+	progress             func(Progress)
+	strict               bool
+	thresholds           *Thresholds
+	onlyFormats          []string
+	withoutFormats       []string
+}
+
+func newAnalyzeOptions(opts []Option) *analyzeOptions {
+	o := &analyzeOptions{
+		subdirectoryMinFiles: DefaultSubdirectoryMinFiles,
+		jobs:                 runtime.GOMAXPROCS(0),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithSubdirectories surveys every directory up to depth levels deep on its own as well, and adds path scoped sections
+// for those which diverge from their parent. Directories with fewer than minFiles matching files are never considered
+// for a format, 0 uses DefaultSubdirectoryMinFiles.
+func WithSubdirectories(depth, minFiles int) Option {
+	return func(o *analyzeOptions) {
+		o.subdirectoryDepth = depth
+		if minFiles > 0 {
+			o.subdirectoryMinFiles = minFiles
+		}
+	}
+}
+
+// WithoutConsolidation keeps every section as the FileFormats produced them, see Consolidate
+func WithoutConsolidation() Option {
+	return func(o *analyzeOptions) {
+		o.skipConsolidation = true
+	}
+}
+
+// WithSampling picks which parts of each file are surveyed, the whole file (SampleFull) by default
+func WithSampling(policy SamplePolicy) Option {
+	return func(o *analyzeOptions) {
+		o.sampling = policy
+	}
+}
+
+// WithJobs reads and surveys up to n files at once, 0 or less uses GOMAXPROCS which is the default
+func WithJobs(n int) Option {
+	return func(o *analyzeOptions) {
+		if n > 0 {
+			o.jobs = n
+		}
+	}
+}
+
+// WithWalkFilter prunes the walk with filter, see WalkFilter. It runs before the ignore function, which only ever sees
+// the files filter lets through.
+func WithWalkFilter(filter WalkFilter) Option {
+	return func(o *analyzeOptions) {
+		o.walkFilter = filter
+	}
+}
+
+// WithReadTimeout gives up on files which take longer than d to read and survey, useful on network filesystems and
+// FUSE mounts, they are counted as SkippedTimeout. Reads can't be interrupted so the file is left being read in the
+// background, and the ignore function may still be called for it after Analyze returns.
+func WithReadTimeout(d time.Duration) Option {
+	return func(o *analyzeOptions) {
+		o.readTimeout = d
+	}
+}
+
+// WithProgress calls fn with the running totals as files are walked, surveyed or skipped. Calls come from several
+// goroutines but never at once, the changes made while fn runs come together in the next call so a slow fn only holds
+// up the goroutine calling it. fn shouldn't call back into Analyze.
+func WithProgress(fn func(Progress)) Option {
+	return func(o *analyzeOptions) {
+		o.progress = fn
+	}
+}
+
+// WithStrict stops at the first file which can't be read or FileFormat which fails and returns its error, by default
+// Analyze carries on without them and lists them in Guess.Errors
+func WithStrict() Option {
+	return func(o *analyzeOptions) {
+		o.strict = true
+	}
+}
+
+// WithThresholds decides properties with t instead of DefaultThresholds
+func WithThresholds(t Thresholds) Option {
+	return func(o *analyzeOptions) {
+		o.thresholds = &t
+	}
+}
+
+// WithOnlyFormats only runs the FileFormats with these names (case insensitive), ie "Go" or "TypeScript"
+func WithOnlyFormats(names ...string) Option {
+	return func(o *analyzeOptions) {
+		o.onlyFormats = append(o.onlyFormats, names...)
+	}
+}
+
+// WithoutFormats leaves out the FileFormats with these names (case insensitive)
+func WithoutFormats(names ...string) Option {
+	return func(o *analyzeOptions) {
+		o.withoutFormats = append(o.withoutFormats, names...)
+	}
+}
+
+// formatEnabled true if the FileFormat is to be run
+func (o *analyzeOptions) formatEnabled(name string) bool {
+	for _, n := range o.withoutFormats {
+		if strings.EqualFold(n, name) {
+			return false
+		}
+	}
+	if len(o.onlyFormats) == 0 {
+		return true
+	}
+	for _, n := range o.onlyFormats {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// validate reports thresholds which can't work and format names which don't match any FileFormat
+func (o *analyzeOptions) validate() error {
+	if o.thresholds != nil {
+		if err := o.thresholds.Validate(); err != nil {
+			return err
+		}
+	}
+	names := map[string]bool{}
+	for _, ff := range FileFormats() {
+		names[strings.ToLower(ff.Name())] = true
+	}
+	for _, n := range append(append([]string{}, o.onlyFormats...), o.withoutFormats...) {
+		if !names[strings.ToLower(n)] {
+			return fmt.Errorf("unknown format %s, see FileFormats", n)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package ecg
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Outlier a file which votes against the value its section settled on
+type Outlier struct {
+	Dissent
+	// Header of the section, ie `*.c`
+	Section string
+	// Share of the files with an opinion on the property which voted for the majority (0 to 1)
+	Share float64
+}
+
+// String ie "src/legacy/foo.c uses CRLF while 97% of *.c use LF"
+func (o *Outlier) String() string {
+	return fmt.Sprintf("%s %s while %0.0f%% of %s %s", o.Filename, describeVote(o.Property, o.Value, false), o.Share*100, o.Section, describeVote(o.Property, o.Majority, true))
+}
+
+// describeVote describes a value of a property as a verb phrase, plural for the majority
+func describeVote(property, value string, plural bool) string {
+	pick := func(singular, many string) string {
+		if plural {
+			return many
+		}
+		return singular
+	}
+	switch property + "=" + normalizeValue(value) {
+	case "end_of_line=lf":
+		return pick("uses LF", "use LF")
+	case "end_of_line=crlf":
+		return pick("uses CRLF", "use CRLF")
+	case "end_of_line=mixed":
+		return pick("mixes LF and CRLF", "mix LF and CRLF")
+	case "indent_style=tab":
+		return pick("indents with tabs", "indent with tabs")
+	case "indent_style=space":
+		return pick("indents with spaces", "indent with spaces")
+	case "insert_final_newline=true":
+		return pick("ends with a new line", "end with a new line")
+	case "insert_final_newline=false":
+		return pick("doesn't end with a new line", "don't end with a new line")
+	case "trim_trailing_whitespace=true":
+		return pick("has no trailing whitespace", "have no trailing whitespace")
+	case "trim_trailing_whitespace=false":
+		return pick("has trailing whitespace", "have trailing whitespace")
+	}
+	return pick("has "+property+" "+value, "have "+property+" "+value)
+}
+
+// Outliers the files which vote against the value of a property their section sets, sorted by file then property.
+// Properties below minConfidence are left out, as are properties where most files vote against the value as some
+// properties are decided by lines rather than files. A file is only reported against the last section setting the
+// property, as that is the one which applies to it.
+func (g *Guess) Outliers(minConfidence float64) []*Outlier {
+	type key struct {
+		filename string
+		property string
+	}
+	found := map[key]*Outlier{}
+	for _, s := range g.Sections {
+		for _, surveyor := range s.Surveyors() {
+			// The share of the section's value is the same for every vote, working it out per vote is quadratic
+			shares := map[string]float64{}
+			for _, p := range s.Properties {
+				if _, ok := shares[p.Key]; p.Key != "" && !ok {
+					shares[p.Key] = surveyor.VoteShare(p.Key, p.Value)
+				}
+			}
+			for _, v := range surveyor.Votes {
+				for property, value := range v.Values {
+					p := s.Get(property)
+					if p == nil || p.Confidence < minConfidence {
+						continue
+					}
+					k := key{filename: v.Filename, property: property}
+					if normalizeValue(value) == normalizeValue(p.Value) {
+						// A later section agreeing with the file overrides an earlier one that doesn't
+						delete(found, k)
+						continue
+					}
+					share := shares[property]
+					if share <= 0.5 {
+						delete(found, k)
+						continue
+					}
+					found[k] = &Outlier{
+						Dissent: Dissent{
+							Filename: v.Filename,
+							Property: property,
+							Value:    value,
+							Majority: p.Value,
+						},
+						Section: s.Header(),
+						Share:   share,
+					}
+				}
+			}
+		}
+	}
+	result := make([]*Outlier, 0, len(found))
+	for _, o := range found {
+		result = append(result, o)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Filename != result[j].Filename {
+			return result[i].Filename < result[j].Filename
+		}
+		return result[i].Property < result[j].Property
+	})
+	return result
+}
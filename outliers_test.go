@@ -0,0 +1,41 @@
+package ecg_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	ecg "editorconfig-guesser"
+	_ "editorconfig-guesser/fileformats"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGuess_Outliers(t *testing.T) {
+	tabs := &fstest.MapFile{Data: []byte("int main() {\n\treturn 0;\n}\n")}
+	mapFS := fstest.MapFS{
+		"a.c":             tabs,
+		"b.c":             tabs,
+		"c.c":             tabs,
+		"d.c":             tabs,
+		"src/legacy/e.c":  &fstest.MapFile{Data: []byte("int main() {\r\n\treturn 0;\r\n}\r\n")},
+		"src/legacy/f.c":  &fstest.MapFile{Data: []byte("int main() {\n    return 0;\n}")},
+		"notes/todo.txt":  &fstest.MapFile{Data: []byte("a\nb\n")},
+		"notes/other.txt": &fstest.MapFile{Data: []byte("a\nb\n")},
+	}
+	guess, err := ecg.Analyze(mapFS, func(f *ecg.File) bool { return false })
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	var got []string
+	for _, o := range guess.Outliers(0) {
+		got = append(got, o.String())
+	}
+	want := []string{
+		// The C section only says what differs from `[*]`, so the line endings and final new lines are `[*]`'s
+		"src/legacy/e.c uses CRLF while 88% of * use LF",
+		"src/legacy/f.c doesn't end with a new line while 88% of * end with a new line",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Outliers() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -4,12 +4,20 @@ import (
 	"bytes"
 	"fmt"
 	"path/filepath"
+	"sort"
 )
 
 // NewPresence ...
 func NewPresence(name string, globs []string, ectemplate []byte) FileFormat {
+	return NewPresenceWithFiletypes(name, globs, nil, ectemplate)
+}
+
+// NewPresenceWithFiletypes a Presence which also claims the files without an extension classified as one of the
+// filetypes (see File.Filetype), they are added to the section by their exact path
+func NewPresenceWithFiletypes(name string, globs []string, filetypes []string, ectemplate []byte) FileFormat {
 	return NewContainer(name, &Presence{
 		globs:      globs,
+		filetypes:  filetypes,
 		ectemplate: ectemplate,
 	})
 }
@@ -17,8 +25,10 @@ func NewPresence(name string, globs []string, ectemplate []byte) FileFormat {
 // Presence ...
 type Presence struct {
 	globs      []string
+	filetypes  []string
 	ectemplate []byte
 	matched    map[int]struct{}
+	paths      []string
 	summary    []*SummaryResult
 }
 
@@ -30,6 +40,7 @@ func (l *Presence) Init() ([]*SummaryResult, error) {
 
 // RunFile ...
 func (l *Presence) RunFile(f *File) ([]*SummaryResult, error) {
+	matched := false
 	for gsi, gs := range l.globs {
 		_, fn := filepath.Split(f.Filename)
 		if m, err := filepath.Match(gs, fn); err != nil {
@@ -37,24 +48,44 @@ func (l *Presence) RunFile(f *File) ([]*SummaryResult, error) {
 		} else if !m {
 			continue
 		}
+		matched = true
 		if _, ok := l.matched[gsi]; ok {
 			continue
 		}
 		l.matched[gsi] = struct{}{}
-		if len(l.summary) == 0 {
-			l.summary = append(l.summary, &SummaryResult{
-				FileGlobs:  []string{gs},
-				Confidence: 1,
-				Template:   ErrorStringerWrapper(bytes.NewBuffer(l.ectemplate)),
-				Path:       "/",
-			})
-		} else {
-			l.summary[0].FileGlobs = append(l.summary[0].FileGlobs, gs)
-		}
+		l.addGlob(gs)
+	}
+	if !matched && len(l.filetypes) > 0 && f.HasFiletype(l.filetypes) {
+		l.paths = append(l.paths, ExactGlob(f.Filename))
 	}
 	return nil, nil
 }
 
+// Claims true if one of the globs matches the file or it is classified as one of the filetypes
+func (l *Presence) Claims(f *File) bool {
+	_, fn := filepath.Split(f.Filename)
+	for _, gs := range l.globs {
+		if m, _ := filepath.Match(gs, fn); m {
+			return true
+		}
+	}
+	return len(l.filetypes) > 0 && f.HasFiletype(l.filetypes)
+}
+
+// addGlob adds the glob to the one section Presence has
+func (l *Presence) addGlob(glob string) {
+	if len(l.summary) == 0 {
+		l.summary = append(l.summary, &SummaryResult{
+			FileGlobs:  []string{glob},
+			Confidence: 1,
+			Template:   ErrorStringerWrapper(bytes.NewBuffer(l.ectemplate)),
+			Path:       "/",
+		})
+	} else {
+		l.summary[0].FileGlobs = append(l.summary[0].FileGlobs, glob)
+	}
+}
+
 // ErrorStringerWrapperStruct ...
 type ErrorStringerWrapperStruct struct {
 	stringer fmt.Stringer
@@ -72,5 +103,10 @@ func ErrorStringerWrapper(stringer fmt.Stringer) ErrorStringer {
 
 // End ...
 func (l *Presence) End() ([]*SummaryResult, error) {
+	// Files arrive in whatever order they were read in
+	sort.Strings(l.paths)
+	for _, p := range l.paths {
+		l.addGlob(p)
+	}
 	return l.summary, nil
 }
@@ -0,0 +1,121 @@
+package ecg
+
+import "sync"
+
+// SkipReason why a file or directory was left out of the survey
+type SkipReason string
+
+const (
+	// SkippedFiltered the WalkFilter pruned the file or directory
+	SkippedFiltered SkipReason = "filtered"
+	// SkippedIgnored the ignore function returned true
+	SkippedIgnored SkipReason = "ignored"
+	// SkippedTimeout reading the file took longer than the read timeout, see WithReadTimeout
+	SkippedTimeout SkipReason = "timeout"
+	// SkippedError the file couldn't be read, see Guess.Errors
+	SkippedError SkipReason = "error"
+	// SkippedCancelled the context was done before the file was read
+	SkippedCancelled SkipReason = "cancelled"
+)
+
+// Progress how far Analyze has got, see WithProgress
+type Progress struct {
+	// Files the walk has reached, surveyed or not
+	Walked int
+	// Files which have been read and handed to the FileFormats
+	Surveyed int
+	// Files and directories left out, by reason
+	Skipped map[SkipReason]int
+}
+
+// SkippedTotal how many files and directories were left out for any reason
+func (p Progress) SkippedTotal() int {
+	n := 0
+	for _, c := range p.Skipped {
+		n += c
+	}
+	return n
+}
+
+// progressTracker counts what Analyze does and passes a copy to the callback after changes, a nil tracker does
+// nothing. The callback is called outside the lock with the latest copy, changes made while it runs are passed on
+// together in the next call rather than waiting for it.
+type progressTracker struct {
+	sync.Mutex
+	progress Progress
+	fn       func(Progress)
+	// Counts the changes, and the change the callback was last called with
+	changes   int
+	delivered int
+	// Held by the goroutine calling the callback
+	calling sync.Mutex
+}
+
+func newProgressTracker(fn func(Progress)) *progressTracker {
+	if fn == nil {
+		return nil
+	}
+	return &progressTracker{
+		progress: Progress{Skipped: map[SkipReason]int{}},
+		fn:       fn,
+	}
+}
+
+func (t *progressTracker) update(change func(p *Progress)) {
+	if t == nil {
+		return
+	}
+	t.Lock()
+	change(&t.progress)
+	t.changes++
+	t.Unlock()
+	// Whoever is calling the callback passes this change on, they check for changes made just as they finished
+	for t.calling.TryLock() {
+		for {
+			p, ok := t.snapshot()
+			if !ok {
+				break
+			}
+			t.fn(p)
+		}
+		t.calling.Unlock()
+		if !t.pending() {
+			return
+		}
+	}
+}
+
+// snapshot a copy of the progress if it has changed since the callback was last called with it
+func (t *progressTracker) snapshot() (Progress, bool) {
+	t.Lock()
+	defer t.Unlock()
+	if t.delivered == t.changes {
+		return Progress{}, false
+	}
+	t.delivered = t.changes
+	p := t.progress
+	p.Skipped = make(map[SkipReason]int, len(t.progress.Skipped))
+	for k, v := range t.progress.Skipped {
+		p.Skipped[k] = v
+	}
+	return p, true
+}
+
+// pending true if there are changes the callback hasn't been called with
+func (t *progressTracker) pending() bool {
+	t.Lock()
+	defer t.Unlock()
+	return t.delivered != t.changes
+}
+
+func (t *progressTracker) walked() {
+	t.update(func(p *Progress) { p.Walked++ })
+}
+
+func (t *progressTracker) surveyed() {
+	t.update(func(p *Progress) { p.Surveyed++ })
+}
+
+func (t *progressTracker) skipped(reason SkipReason) {
+	t.update(func(p *Progress) { p.Skipped[reason]++ })
+}
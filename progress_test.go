@@ -0,0 +1,115 @@
+package ecg_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	ecg "editorconfig-guesser"
+	_ "editorconfig-guesser/fileformats"
+	"github.com/google/go-cmp/cmp"
+)
+
+// blockingFS blocks opening a file until release is closed
+type blockingFS struct {
+	fs.FS
+	name    string
+	release chan struct{}
+}
+
+func (b *blockingFS) Open(name string) (fs.File, error) {
+	if name == b.name {
+		<-b.release
+	}
+	return b.FS.Open(name)
+}
+
+func TestWithProgress(t *testing.T) {
+	dir := &blockingFS{
+		FS: fstest.MapFS{
+			"main.go":       &fstest.MapFile{Data: []byte("package main\n")},
+			"util.go":       &fstest.MapFile{Data: []byte("package main\n")},
+			"slow.go":       &fstest.MapFile{Data: []byte("package main\n")},
+			"ignored.txt":   &fstest.MapFile{Data: []byte("ignored\n")},
+			"vendor/a/a.go": &fstest.MapFile{Data: []byte("package a\n")},
+		},
+		name:    "slow.go",
+		release: make(chan struct{}),
+	}
+	defer close(dir.release)
+	var last ecg.Progress
+	_, err := ecg.Analyze(dir, func(f *ecg.File) bool {
+		return f.Filename == "ignored.txt"
+	}, ecg.WithWalkFilter(func(path string, d fs.DirEntry) error {
+		if path == "vendor" {
+			return fs.SkipDir
+		}
+		return nil
+	}), ecg.WithReadTimeout(50*time.Millisecond), ecg.WithProgress(func(p ecg.Progress) {
+		last = p
+	}))
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	want := ecg.Progress{
+		Walked:   4,
+		Surveyed: 2,
+		Skipped: map[ecg.SkipReason]int{
+			ecg.SkippedFiltered: 1,
+			ecg.SkippedIgnored:  1,
+			ecg.SkippedTimeout:  1,
+		},
+	}
+	if diff := cmp.Diff(want, last); diff != "" {
+		t.Errorf("Progress mismatch (-want +got):\n%s", diff)
+	}
+	if got := last.SkippedTotal(); got != 3 {
+		t.Errorf("SkippedTotal() = %d, want 3", got)
+	}
+}
+
+func TestWithProgress_SlowCallback(t *testing.T) {
+	dir := fstest.MapFS{}
+	for i := 0; i < 50; i++ {
+		dir[fmt.Sprintf("f%d.go", i)] = &fstest.MapFile{Data: []byte("package main\n")}
+	}
+	var running atomic.Int32
+	var last ecg.Progress
+	_, err := ecg.Analyze(dir, func(f *ecg.File) bool { return false }, ecg.WithJobs(8), ecg.WithProgress(func(p ecg.Progress) {
+		if running.Add(1) > 1 {
+			t.Error("the callback was called while it was still running")
+		}
+		defer running.Add(-1)
+		if p.Walked < last.Walked || p.Surveyed < last.Surveyed {
+			t.Errorf("Progress went from %+v back to %+v", last, p)
+		}
+		last = p
+		time.Sleep(time.Millisecond)
+	}))
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if last.Walked != 50 || last.Surveyed != 50 {
+		t.Errorf("last Progress = %+v, want 50 walked and surveyed", last)
+	}
+}
+
+func TestAnalyzeContextCancelled(t *testing.T) {
+	dir := fstest.MapFS{
+		"main.go": &fstest.MapFile{Data: []byte("package main\n")},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	guess, err := ecg.AnalyzeContext(ctx, dir, func(f *ecg.File) bool { return false })
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("AnalyzeContext() error = %v, want %v", err, context.Canceled)
+	}
+	if guess != nil {
+		t.Errorf("AnalyzeContext() = %v, want nil", guess)
+	}
+}
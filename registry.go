@@ -23,21 +23,37 @@ func FileFormats() []FileFormat {
 	var af *BasicSurveyor
 	for i, fff := range fileFormats {
 		ffs[i] = fff()
-		if afg, ok := ffs[i].(BasicSurveyorGetter); ok {
+		if afg, ok := fileRunner(ffs[i]).(BasicSurveyorGetter); ok {
 			af = afg.BasicSurveyor()
 		}
 	}
 	if af != nil {
 		for _, ff := range ffs {
-			if afs, ok := ff.(BasicSurveyorSetter); ok {
+			if afs, ok := fileRunner(ff).(BasicSurveyorSetter); ok {
 				afs.SetBasicSurveyor(af)
 			}
 		}
 	}
 	sort.Sort(FileFormatsSorter(ffs))
+	for _, ff := range ffs {
+		if c, ok := ff.(*Container); ok {
+			if fs, ok := c.FileRunner.(FileFormatsSetter); ok {
+				fs.SetFileFormats(ffs)
+			}
+		}
+	}
 	return ffs
 }
 
+// fileRunner the FileRunner of a Container, which is where formats implement the optional interfaces, otherwise the
+// FileFormat itself
+func fileRunner(ff FileFormat) any {
+	if c, ok := ff.(*Container); ok {
+		return c.FileRunner
+	}
+	return ff
+}
+
 // FileFormatsSorter ...
 type FileFormatsSorter []FileFormat
 
@@ -0,0 +1,115 @@
+package ecg
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Report a machine readable summary of a Guess, see Guess.Report
+type Report struct {
+	// The directory the guess is of, left to the caller to fill in
+	Directory string           `json:"directory,omitempty" yaml:"directory,omitempty"`
+	Root      bool             `json:"root" yaml:"root"`
+	Sections  []*SectionReport `json:"sections" yaml:"sections"`
+	// What couldn't be surveyed, see Guess.Errors
+	Errors []*ErrorReport `json:"errors,omitempty" yaml:"errors,omitempty"`
+	// See Guess.Warnings
+	Warnings []*Warning `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+}
+
+// ErrorReport a FileError of a Report
+type ErrorReport struct {
+	Filename string `json:"filename,omitempty" yaml:"filename,omitempty"`
+	Format   string `json:"format,omitempty" yaml:"format,omitempty"`
+	Error    string `json:"error" yaml:"error"`
+}
+
+// SectionReport a section of a Report
+type SectionReport struct {
+	Header     string   `json:"header" yaml:"header"`
+	Globs      []string `json:"globs" yaml:"globs"`
+	Format     string   `json:"format,omitempty" yaml:"format,omitempty"`
+	Path       string   `json:"path" yaml:"path"`
+	Confidence float64  `json:"confidence" yaml:"confidence"`
+	// Number of files surveyed, 0 for formats which don't survey the files (ie Presence)
+	Files      int               `json:"files" yaml:"files"`
+	Properties []*PropertyReport `json:"properties" yaml:"properties"`
+	// Number of files in each character set the detector found, "" for files it couldn't tell
+	Charsets map[string]int `json:"charsets,omitempty" yaml:"charsets,omitempty"`
+	// Files which disagree with the majority
+	Dissents []*Dissent `json:"dissents,omitempty" yaml:"dissents,omitempty"`
+}
+
+// PropertyReport a property of a SectionReport
+type PropertyReport struct {
+	Key        string  `json:"key" yaml:"key"`
+	Value      string  `json:"value" yaml:"value"`
+	Confidence float64 `json:"confidence" yaml:"confidence"`
+	Comment    string  `json:"comment,omitempty" yaml:"comment,omitempty"`
+}
+
+// Report summarises the guess along with the statistics behind it, properties below opts.MinConfidence are left out
+func (g *Guess) Report(opts RenderOptions) *Report {
+	r := &Report{
+		Root:     g.IsRoot(),
+		Sections: []*SectionReport{},
+		Warnings: g.Warnings,
+	}
+	for _, e := range g.Errors {
+		r.Errors = append(r.Errors, &ErrorReport{
+			Filename: e.Filename,
+			Format:   e.Format,
+			Error:    e.Err.Error(),
+		})
+	}
+	for _, s := range g.Sections {
+		sr := &SectionReport{
+			Header:     s.Header(),
+			Globs:      s.FileGlobs,
+			Format:     s.Format,
+			Path:       s.Path,
+			Confidence: s.Confidence,
+			Properties: []*PropertyReport{},
+		}
+		for _, p := range s.Properties {
+			if p.Key == "" || p.Confidence < opts.MinConfidence {
+				continue
+			}
+			sr.Properties = append(sr.Properties, &PropertyReport{
+				Key:        p.Key,
+				Value:      p.Value,
+				Confidence: p.Confidence,
+				Comment:    p.Comment,
+			})
+		}
+		for _, surveyor := range s.Surveyors() {
+			sr.Files += surveyor.Files
+			for charset, n := range surveyor.CharacterSets.Sets {
+				if sr.Charsets == nil {
+					sr.Charsets = map[string]int{}
+				}
+				sr.Charsets[charset] += n
+			}
+			sr.Dissents = append(sr.Dissents, surveyor.Dissents()...)
+		}
+		r.Sections = append(r.Sections, sr)
+	}
+	return r
+}
+
+// Marshal the report as "json" or "yaml"
+func (r *Report) Marshal(format string) ([]byte, error) {
+	switch format {
+	case "json":
+		b, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(b, '\n'), nil
+	case "yaml":
+		return yaml.Marshal(r)
+	}
+	return nil, fmt.Errorf("unknown report format: %s", format)
+}
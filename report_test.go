@@ -0,0 +1,56 @@
+package ecg_test
+
+import (
+	"encoding/json"
+	"testing"
+	"testing/fstest"
+
+	ecg "editorconfig-guesser"
+	_ "editorconfig-guesser/fileformats"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGuess_Report(t *testing.T) {
+	lf := &fstest.MapFile{Data: []byte("a\nb\n")}
+	mapFS := fstest.MapFS{
+		"a.txt": lf,
+		"b.txt": lf,
+		"c.txt": lf,
+		"d.txt": lf,
+		"e.txt": lf,
+		"f.txt": &fstest.MapFile{Data: []byte("a\r\nb\r\n")},
+	}
+	guess, err := ecg.Analyze(mapFS, func(f *ecg.File) bool { return false })
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	report := guess.Report(ecg.RenderOptions{MinConfidence: 0.5})
+	all := report.Sections[0]
+	if all.Header != "*" || all.Files != 6 {
+		t.Errorf("Sections[0] = %s with %d files, want * with 6", all.Header, all.Files)
+	}
+	want := []*ecg.Dissent{{Filename: "f.txt", Property: "end_of_line", Value: "crlf", Majority: "lf"}}
+	if diff := cmp.Diff(want, all.Dissents); diff != "" {
+		t.Errorf("Dissents mismatch (-want +got):\n%s", diff)
+	}
+	for _, p := range all.Properties {
+		if p.Confidence < 0.5 {
+			t.Errorf("Property %s has confidence %f below the minimum", p.Key, p.Confidence)
+		}
+	}
+	b, err := report.Marshal("json")
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var roundTrip ecg.Report
+	if err := json.Unmarshal(b, &roundTrip); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if diff := cmp.Diff(report, &roundTrip); diff != "" {
+		t.Errorf("JSON round trip mismatch (-want +got):\n%s", diff)
+	}
+	if _, err := report.Marshal("toml"); err == nil {
+		t.Error("Marshal(toml) should fail")
+	}
+}
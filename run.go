@@ -2,10 +2,14 @@
 package ecg
 
 import (
+	"context"
 	_ "embed"
 	"fmt"
 	"io/fs"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 var (
@@ -15,65 +19,341 @@ var (
 
 // RunInDir ...
 func RunInDir(dir fs.FS, ignore func(file *File) bool) (string, error) {
-	ff := FileFormats()
-	chans := make([]chan *File, 0, len(ff))
-	for _, eff := range ff {
-		chans = append(chans, eff.Start())
+	guess, err := Analyze(dir, ignore)
+	if err != nil {
+		return "", err
 	}
-	fn := func(path string, d fs.DirEntry, _ error) error {
-		if d == nil || d.IsDir() {
+	return guess.String(), nil
+}
+
+// RunInDirContext RunInDir which stops once ctx is done, see AnalyzeContext
+func RunInDirContext(ctx context.Context, dir fs.FS, ignore func(file *File) bool, opts ...Option) (string, error) {
+	guess, err := AnalyzeContext(ctx, dir, ignore, opts...)
+	if err != nil {
+		return "", err
+	}
+	return guess.String(), nil
+}
+
+// Analyze surveys every file in dir which isn't ignored and returns the resulting sections. Files are read and
+// surveyed by a pool of workers (see WithJobs), so ignore may be called from several goroutines at once.
+func Analyze(dir fs.FS, ignore func(file *File) bool, opts ...Option) (*Guess, error) {
+	return AnalyzeContext(context.Background(), dir, ignore, opts...)
+}
+
+// AnalyzeContext Analyze which stops walking and reading files once ctx is done. The files already handed to the
+// FileFormats are drained so every goroutine it started has finished when it returns ctx's error.
+func AnalyzeContext(ctx context.Context, dir fs.FS, ignore func(file *File) bool, opts ...Option) (*Guess, error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	a := &analysis{
+		ctx:    ctx,
+		cancel: cancel,
+		ignore: ignore,
+		opts:   newAnalyzeOptions(opts),
+		errors: &fileErrors{},
+	}
+	o := a.opts
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+	a.progress = newProgressTracker(o.progress)
+	root := newScope("/", 0, o)
+	scopes := []*scope{root}
+	jobs := make(chan *job, o.jobs*2)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < o.jobs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				j.run(a)
+			}
+		}()
+	}
+	fn := func(path string, d fs.DirEntry, err error) error {
+		if cause := context.Cause(ctx); cause != nil {
+			return cause
+		}
+		if err != nil && path == "." {
+			// Nothing can be surveyed without the directory itself
+			return err
+		}
+		if err != nil {
+			// The file or directory can't be read, everything else can still be surveyed
+			if err := a.fail(&FileError{Filename: path, Err: err}); err != nil {
+				return err
+			}
+			a.progress.skipped(SkippedError)
 			return nil
 		}
-		f := &File{
-			Filename:   path,
-			FileOpener: dir,
+		if skip, err := o.skip(path, d); skip {
+			a.progress.skipped(SkippedFiltered)
+			return err
 		}
-		if ignore(f) {
+		if d.IsDir() {
+			if depth := pathDepth(path); path != "." && depth <= o.subdirectoryDepth {
+				scopes = append(scopes, newScope("/"+path, depth, o))
+			}
 			return nil
 		}
-		for _, e := range chans {
-			e <- f
+		j := &job{
+			file: &File{
+				Filename:   path,
+				FileOpener: dir,
+				Sampling:   o.sampling,
+			},
 		}
+		for _, s := range scopes {
+			if s.contains(path) {
+				j.scopes = append(j.scopes, s)
+			}
+		}
+		a.progress.walked()
+		jobs <- j
 		return nil
 	}
-	if err := fs.WalkDir(dir, ".", fn); err != nil {
-		return "", fmt.Errorf("walking %s: %w", dir, err)
+	err := fs.WalkDir(dir, ".", fn)
+	close(jobs)
+	wg.Wait()
+	for _, s := range scopes {
+		s.send(nil)
 	}
-	for _, e := range chans {
-		e <- nil
+	if err == nil {
+		// Cancelled once the walk was over
+		err = context.Cause(ctx)
 	}
-	template := &strings.Builder{}
-	_, _ = template.Write(rootectemplate)
-	for _, eff := range ff {
-		ss, err := eff.Done()
-		if err != nil {
-			return "", err
+	if err != nil {
+		for _, s := range scopes {
+			s.wait()
 		}
-		for i, ess := range ss {
-			if i > 0 {
-				_, _ = fmt.Fprintln(template)
-				_, _ = fmt.Fprintln(template)
-			}
-			_, _ = fmt.Fprintf(template, "[")
-			if len(ess.FileGlobs) > 1 {
-				_, _ = fmt.Fprintf(template, "{")
+		return nil, fmt.Errorf("walking %s: %w", dir, err)
+	}
+	guess := &Guess{
+		Preamble: ParseProperties(string(rootectemplate)),
+	}
+	for _, s := range scopes {
+		if err := s.done(a); err != nil {
+			return nil, err
+		}
+	}
+	guess.Sections = root.sections
+	guess.Sections = append(guess.Sections, divergentSections(scopes, o.subdirectoryMinFiles)...)
+	if !o.skipConsolidation {
+		guess.Sections = Consolidate(guess.Sections)
+	}
+	guess.Errors = a.errors.sorted()
+	// Subdirectory scopes survey the same files again
+	guess.Warnings = root.warnings
+	sortWarnings(guess.Warnings)
+	guess.Files = int(a.surveyed.Load())
+	return guess, nil
+}
+
+// analysis the state shared by the walk, the workers and the scopes
+type analysis struct {
+	ctx      context.Context
+	cancel   context.CancelCauseFunc
+	ignore   func(file *File) bool
+	opts     *analyzeOptions
+	progress *progressTracker
+	errors   *fileErrors
+	// How many files were surveyed
+	surveyed atomic.Int64
+}
+
+// fail records errors, unless the analysis is strict where the first is returned to stop it
+func (a *analysis) fail(errs ...*FileError) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	if a.opts.strict {
+		return errs[0]
+	}
+	a.errors.add(errs...)
+	return nil
+}
+
+// job a file waiting for a worker along with the scopes it belongs to, worked out as it was walked
+type job struct {
+	file   *File
+	scopes []*scope
+}
+
+// run reads the file once, so the formats it is sent to all share the result, then queues it with them. Files which
+// can't be read are left out.
+func (j *job) run(a *analysis) {
+	if a.ctx.Err() != nil {
+		a.progress.skipped(SkippedCancelled)
+		return
+	}
+	reason, err := j.read(a)
+	if err != nil {
+		if err := a.fail(&FileError{Filename: j.file.Filename, Err: err}); err != nil {
+			a.cancel(err)
+		}
+	}
+	if reason != "" {
+		a.progress.skipped(reason)
+		return
+	}
+	for _, s := range j.scopes {
+		s.send(j.file)
+	}
+	a.surveyed.Add(1)
+	a.progress.surveyed()
+}
+
+// read runs ignore and surveys the file, with a timeout it gives up on the file once the timeout or ctx is done and
+// leaves it being read in the background. Returns why the file was skipped or "" if it was surveyed, along with the
+// error for SkippedError.
+func (j *job) read(a *analysis) (SkipReason, error) {
+	type result struct {
+		reason SkipReason
+		err    error
+	}
+	survey := func() result {
+		if a.ignore(j.file) {
+			return result{reason: SkippedIgnored}
+		}
+		if _, err := j.file.Survey(); err != nil {
+			return result{reason: SkippedError, err: err}
+		}
+		return result{}
+	}
+	if a.opts.readTimeout <= 0 {
+		r := survey()
+		return r.reason, r.err
+	}
+	done := make(chan result, 1)
+	go func() {
+		done <- survey()
+	}()
+	t := time.NewTimer(a.opts.readTimeout)
+	defer t.Stop()
+	select {
+	case r := <-done:
+		return r.reason, r.err
+	case <-t.C:
+		return SkippedTimeout, nil
+	case <-a.ctx.Done():
+		return SkippedCancelled, nil
+	}
+}
+
+// scope a directory being surveyed with its own set of FileFormats
+type scope struct {
+	path     string
+	depth    int
+	formats  []FileFormat
+	chans    []chan *File
+	sections []*Section
+	warnings []*Warning
+}
+
+func newScope(path string, depth int, o *analyzeOptions) *scope {
+	s := &scope{
+		path:  path,
+		depth: depth,
+	}
+	thresholds := DefaultThresholds()
+	if o.thresholds != nil {
+		thresholds = *o.thresholds
+	}
+	for _, ff := range FileFormats() {
+		if !o.formatEnabled(ff.Name()) {
+			continue
+		}
+		if ts, ok := fileRunner(ff).(ThresholdsSetter); ok {
+			ts.SetThresholds(thresholds)
+		}
+		s.formats = append(s.formats, ff)
+	}
+	for _, eff := range s.formats {
+		s.chans = append(s.chans, eff.Start())
+	}
+	return s
+}
+
+// contains true if the file is within the scope's directory
+func (s *scope) contains(filename string) bool {
+	return s.path == "/" || strings.HasPrefix("/"+filename, s.path+"/")
+}
+
+func (s *scope) send(f *File) {
+	for _, e := range s.chans {
+		e <- f
+	}
+}
+
+// wait waits for every format to finish, discarding the results
+func (s *scope) wait() {
+	for _, eff := range s.formats {
+		_, _ = eff.Done()
+	}
+}
+
+// done waits for every format and converts their results into sections, what fails is left out unless the analysis
+// is strict
+func (s *scope) done(a *analysis) error {
+	// Every format finishes before any is rendered, templates may look at other formats, see FileFormatsSetter
+	results := make([][]*SummaryResult, len(s.formats))
+	errs := make([]error, len(s.formats))
+	for i, eff := range s.formats {
+		results[i], errs[i] = eff.Done()
+	}
+	for i, eff := range s.formats {
+		if err := a.fail(splitErrors(eff.Name(), errs[i])...); err != nil {
+			return err
+		}
+		for i, ess := range results[i] {
+			for _, w := range ess.Warnings {
+				w.Format = eff.Name()
+				s.warnings = append(s.warnings, w)
 			}
-			for gi, eg := range ess.FileGlobs {
-				if gi > 0 {
-					_, _ = fmt.Fprintf(template, ",")
+			section, err := NewSection(eff.Name(), ess)
+			if err != nil {
+				if err := a.fail(&FileError{Format: eff.Name(), Err: err}); err != nil {
+					return err
 				}
-				_, _ = fmt.Fprint(template, eg)
-			}
-			if len(ess.FileGlobs) > 1 {
-				_, _ = fmt.Fprintf(template, "}")
+				continue
 			}
-			_, _ = fmt.Fprintf(template, "]\n")
-			ts, err := ess.Template.String()
-			if err != nil {
-				return "", err
+			section.Spaced = i > 0
+			s.sections = append(s.sections, section)
+		}
+	}
+	return nil
+}
+
+// NewSection renders the SummaryResult's template and parses it into a Section
+func NewSection(format string, sr *SummaryResult) (*Section, error) {
+	ts, err := sr.Template.String()
+	if err != nil {
+		return nil, fmt.Errorf("%s template: %w", format, err)
+	}
+	properties := ParseProperties(ts)
+	for _, p := range properties {
+		if p.Key == "" {
+			continue
+		}
+		p.Confidence = sr.Confidence
+		if sr.Surveyor != nil {
+			if c, ok := sr.Surveyor.Confidence[p.Key]; ok {
+				p.Confidence = c
 			}
-			_, _ = fmt.Fprintln(template, ts)
 		}
 	}
-	return template.String(), nil
+	if ts == "" || strings.HasSuffix(ts, "\n") {
+		// Templates have always been followed by a new line
+		properties = append(properties, &Property{})
+	}
+	return &Section{
+		Format:     format,
+		FileGlobs:  sr.FileGlobs,
+		Path:       sr.Path,
+		Confidence: sr.Confidence,
+		Properties: properties,
+		Surveyor:   sr.Surveyor,
+		Result:     sr,
+	}, nil
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"embed"
 	ecg "editorconfig-guesser"
+	"fmt"
 	_ "editorconfig-guesser/fileformats"
 	"io/fs"
 	"strings"
@@ -68,3 +69,101 @@ func TestRunInDir(t *testing.T) {
 		})
 	}
 }
+
+func TestAnalyze(t *testing.T) {
+	mapFS := fstest.MapFS{
+		"main.go":   &fstest.MapFile{Data: []byte("package main\n\nfunc main() {\n\tprintln()\n}\n")},
+		"script.sh": &fstest.MapFile{Data: []byte("#!/bin/sh\nif true; then\n  echo hi\nfi\n")},
+	}
+	guess, err := ecg.Analyze(mapFS, func(f *ecg.File) bool { return false })
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	var headers []string
+	for _, s := range guess.Sections {
+		headers = append(headers, s.Format+" "+s.Header())
+	}
+	if diff := cmp.Diff([]string{"ALl Files *", "Go *.go", "Shell *.sh"}, headers); diff != "" {
+		t.Errorf("Sections mismatch (-want +got):\n%s", diff)
+	}
+	all := guess.Section("*")
+	if all.Surveyor == nil || all.Surveyor.Files != 2 {
+		t.Errorf("[*] Surveyor = %+v, want 2 files", all.Surveyor)
+	}
+	if p := all.Get("end_of_line"); p == nil || p.Value != "lf" {
+		t.Errorf("[*] end_of_line = %+v, want lf", p)
+	}
+	if s := guess.Section("*.go"); s.Surveyor == nil || s.Surveyor.Files != 1 || s.Get("indent_style").Value != "tab" || s.Get("indent_style").Confidence != 1 {
+		t.Errorf("[*.go] = %+v, want a surveyed section with a certain tab", s)
+	}
+	if got, err := ecg.RunInDir(mapFS, func(f *ecg.File) bool { return false }); err != nil || got != guess.String() {
+		t.Errorf("RunInDir() = %q, %v; want %q", got, err, guess.String())
+	}
+}
+
+func TestAnalyzeSubdirectories(t *testing.T) {
+	spaces := &fstest.MapFile{Data: []byte("function start() {\n  if (true) {\n    return;\n  }\n}\n")}
+	tabs := &fstest.MapFile{Data: []byte("class A {\n\tstart() {\n\t\tif (true) {\n\t\t\treturn;\n\t\t}\n\t}\n\tstop() {\n\t\treturn;\n\t}\n}\n")}
+	mapFS := fstest.MapFS{
+		"frontend/a.ts":     spaces,
+		"frontend/b.ts":     spaces,
+		"frontend/c.ts":     spaces,
+		"backend/a.ts":      tabs,
+		"backend/b.ts":      tabs,
+		"backend/c.ts":      tabs,
+		"backend/lib/a.ts":  tabs,
+		"scripts/one.ts":    spaces,
+		"scripts/other.txt": spaces,
+	}
+	guess, err := ecg.Analyze(mapFS, func(f *ecg.File) bool { return false }, ecg.WithSubdirectories(2, 3))
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	got := map[string]map[string]string{}
+	for _, s := range guess.Sections {
+		if s.Path == "/" {
+			continue
+		}
+		got[s.Path+" "+s.Header()] = map[string]string{}
+		for _, k := range []string{"indent_style", "indent_size"} {
+			if p := s.Get(k); p != nil {
+				got[s.Path+" "+s.Header()][k] = p.Value
+			}
+		}
+	}
+	want := map[string]map[string]string{
+		"/backend {backend/**.ts,backend/**.js}":   {"indent_style": "tabs"},
+		"/frontend {frontend/**.ts,frontend/**.js}": {"indent_style": "spaces", "indent_size": "2"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Subdirectory sections mismatch (-want +got):\n%s\n%s", diff, guess)
+	}
+	nested := guess.Split()
+	if s := nested["/frontend"].Section("{*.ts,*.js}"); s == nil {
+		t.Errorf("Split() /frontend = %s, want a {*.ts,*.js} section", nested["/frontend"])
+	}
+}
+
+func TestAnalyzeJobs(t *testing.T) {
+	mapFS := fstest.MapFS{}
+	for i := 0; i < 40; i++ {
+		mapFS[fmt.Sprintf("src/%d.ts", i)] = &fstest.MapFile{Data: []byte("function start() {\n  if (true) {\n    return;\n  }\n}\n")}
+		mapFS[fmt.Sprintf("src/%d.go", i)] = &fstest.MapFile{Data: []byte("package main\n\nfunc main() {\n\tprintln()\n}\n")}
+	}
+	mapFS["src/crlf.ts"] = &fstest.MapFile{Data: []byte("function start() {\r\n  return;\r\n}")}
+	ignore := func(f *ecg.File) bool { return false }
+	serial, err := ecg.Analyze(mapFS, ignore, ecg.WithJobs(1), ecg.WithSubdirectories(1, 0))
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	parallel, err := ecg.Analyze(mapFS, ignore, ecg.WithJobs(8), ecg.WithSubdirectories(1, 0))
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if diff := cmp.Diff(serial.String(), parallel.String()); diff != "" {
+		t.Errorf("WithJobs(8) mismatch (-serial +parallel):\n%s", diff)
+	}
+	if diff := cmp.Diff(serial.Outliers(0), parallel.Outliers(0)); diff != "" {
+		t.Errorf("WithJobs(8) outliers mismatch (-serial +parallel):\n%s", diff)
+	}
+}
@@ -0,0 +1,162 @@
+package ecg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+)
+
+// SamplePolicy which parts of a file are surveyed
+type SamplePolicy int
+
+const (
+	// SampleFull surveys the whole file
+	SampleFull SamplePolicy = iota
+	// SampleHead surveys the first ReadSize bytes
+	SampleHead
+	// SampleHeadMiddleTail surveys ReadSize bytes from the start, the middle and the end of the file
+	SampleHeadMiddleTail
+)
+
+// ChunkSize how much of a file is read at a time
+const ChunkSize = 32 * 1024
+
+// String ...
+func (p SamplePolicy) String() string {
+	switch p {
+	case SampleFull:
+		return "full"
+	case SampleHead:
+		return "head"
+	case SampleHeadMiddleTail:
+		return "head-middle-tail"
+	}
+	return fmt.Sprintf("SamplePolicy(%d)", int(p))
+}
+
+// ParseSamplePolicy parses the String() of a SamplePolicy
+func ParseSamplePolicy(s string) (SamplePolicy, error) {
+	for _, p := range []SamplePolicy{SampleFull, SampleHead, SampleHeadMiddleTail} {
+		if p.String() == s {
+			return p, nil
+		}
+	}
+	return SampleFull, fmt.Errorf("unknown sample policy %q, expected full, head or head-middle-tail", s)
+}
+
+// sampleRange a part of a file to survey
+type sampleRange struct {
+	start, end int64
+}
+
+// ranges the parts of a file of the size to survey
+func (p SamplePolicy) ranges(size int64) []sampleRange {
+	const sample = int64(ReadSize)
+	switch {
+	case p == SampleHead && size > sample:
+		return []sampleRange{{0, sample}}
+	case p == SampleHeadMiddleTail && size > 3*sample:
+		middle := size/2 - sample/2
+		return []sampleRange{{0, sample}, {middle, middle + sample}, {size - sample, size}}
+	}
+	return []sampleRange{{0, size}}
+}
+
+// sampleSource a file whose head has already been read, the rest is only opened if a sample needs it
+type sampleSource struct {
+	head []byte
+	size int64
+	open func() (io.ReadSeekCloser, error)
+	f    io.ReadSeekCloser
+}
+
+// readAt reads into b from the offset, from the head when it can
+func (s *sampleSource) readAt(b []byte, offset int64) (int, error) {
+	if offset < int64(len(s.head)) {
+		return copy(b, s.head[offset:]), nil
+	}
+	if s.f == nil {
+		f, err := s.open()
+		if err != nil {
+			return 0, err
+		}
+		s.f = f
+	}
+	if _, err := s.f.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return s.f.Read(b)
+}
+
+func (s *sampleSource) close(filename string) {
+	if s.f == nil {
+		return
+	}
+	if err := s.f.Close(); err != nil {
+		log.Printf("Error: closing %s: %s", filename, err)
+	}
+}
+
+// surveyFile streams the parts of the file the policy picks through a LineSurveyor, and any ContentSurveyors, in
+// ChunkSize pieces. Parts after the first start at their first new line so no partial lines or runes are surveyed.
+func surveyFile(src *sampleSource, policy SamplePolicy, content ...ContentSurveyor) (finalNewLine bool, survey *LineSurvey, err error) {
+	ls := NewLineSurveyor()
+	chunk := make([]byte, ChunkSize)
+	var last byte
+	ranges := policy.ranges(src.size)
+	for i, r := range ranges {
+		skipLine := i > 0
+		if skipLine {
+			ls.Break()
+			for _, cs := range content {
+				cs.Break()
+			}
+		}
+		for pos := r.start; pos < r.end; {
+			n, err := src.readAt(chunk[:min(int64(len(chunk)), r.end-pos)], pos)
+			if n > 0 {
+				b := chunk[:n]
+				pos += int64(n)
+				last = b[n-1]
+				if skipLine {
+					nl := bytes.IndexByte(b, '\n')
+					if nl < 0 {
+						continue
+					}
+					b = b[nl+1:]
+					skipLine = false
+				}
+				_, _ = ls.Write(b)
+				for _, cs := range content {
+					if _, err := cs.Write(b); err != nil {
+						return false, nil, fmt.Errorf("surveying content: %w", err)
+					}
+				}
+			}
+			if errors.Is(err, io.EOF) || (err == nil && n == 0) {
+				break
+			}
+			if err != nil {
+				return false, nil, fmt.Errorf("read %d (of %d): %w", pos, src.size, err)
+			}
+		}
+	}
+	if src.size > 0 {
+		if ranges[len(ranges)-1].end == src.size {
+			finalNewLine = last == '\n'
+		} else {
+			b := make([]byte, 1)
+			if n, err := src.readAt(b, src.size-1); err != nil && !errors.Is(err, io.EOF) {
+				return false, nil, fmt.Errorf("read last byte: %w", err)
+			} else {
+				finalNewLine = n > 0 && b[0] == '\n'
+			}
+		}
+	}
+	for _, cs := range content {
+		cs.End()
+	}
+	return finalNewLine, ls.Survey(), nil
+}
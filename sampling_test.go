@@ -0,0 +1,97 @@
+package ecg
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+type readSeekNopCloser struct {
+	*bytes.Reader
+}
+
+func (readSeekNopCloser) Close() error {
+	return nil
+}
+
+// recordingSurveyor a ContentSurveyor which keeps what it is given
+type recordingSurveyor struct {
+	bytes.Buffer
+	breaks int
+	ended  bool
+}
+
+func (r *recordingSurveyor) Break() {
+	r.breaks++
+}
+
+func (r *recordingSurveyor) End() {
+	r.ended = true
+}
+
+func TestSurveyFile(t *testing.T) {
+	lf := strings.Repeat("unix line\n", int(ReadSize)/10*2)
+	crlf := strings.Repeat("windows line\r\n", int(ReadSize)/14*2)
+	content := []byte(lf + crlf + lf + "é")
+	tests := []struct {
+		policy       SamplePolicy
+		wantWindows  bool
+		wantNewLines int
+		wantBreaks   int
+	}{
+		{policy: SampleHead, wantWindows: false, wantNewLines: int(ReadSize) / 10},
+		{policy: SampleHeadMiddleTail, wantWindows: true, wantBreaks: 2},
+		{policy: SampleFull, wantWindows: true, wantNewLines: strings.Count(string(content), "\n")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			src := &sampleSource{
+				head: content[:ReadSize],
+				size: int64(len(content)),
+				open: func() (io.ReadSeekCloser, error) {
+					return readSeekNopCloser{bytes.NewReader(content)}, nil
+				},
+			}
+			recorded := &recordingSurveyor{}
+			finalNewLine, survey, err := surveyFile(src, tt.policy, recorded)
+			if err != nil {
+				t.Fatalf("surveyFile() error = %v", err)
+			}
+			if finalNewLine {
+				t.Error("surveyFile() finalNewLine = true, want false")
+			}
+			if got := survey.WindowNewlines > 0; got != tt.wantWindows {
+				t.Errorf("surveyFile() saw windows new lines = %v, want %v", got, tt.wantWindows)
+			}
+			if tt.wantNewLines > 0 && survey.NewLines != tt.wantNewLines {
+				t.Errorf("surveyFile() NewLines = %d, want %d", survey.NewLines, tt.wantNewLines)
+			}
+			if survey.NonASCII != 1 && tt.policy != SampleHead {
+				t.Errorf("surveyFile() NonASCII = %d, want 1", survey.NonASCII)
+			}
+			if recorded.breaks != tt.wantBreaks || !recorded.ended {
+				t.Errorf("ContentSurveyor had %d breaks and ended %v, want %d and true", recorded.breaks, recorded.ended, tt.wantBreaks)
+			}
+			if tt.policy == SampleFull && !bytes.Equal(recorded.Bytes(), content) {
+				t.Errorf("ContentSurveyor was given %d bytes, want the whole %d", recorded.Len(), len(content))
+			}
+			for k := range survey.LineLengths {
+				if k.length != 9 && k.length != 12 && k.length != 1 {
+					t.Errorf("surveyFile() surveyed a partial line of length %d", k.length)
+				}
+			}
+		})
+	}
+}
+
+func TestParseSamplePolicy(t *testing.T) {
+	for _, p := range []SamplePolicy{SampleFull, SampleHead, SampleHeadMiddleTail} {
+		if got, err := ParseSamplePolicy(p.String()); err != nil || got != p {
+			t.Errorf("ParseSamplePolicy(%q) = %v, %v", p.String(), got, err)
+		}
+	}
+	if _, err := ParseSamplePolicy("tail"); err == nil {
+		t.Error("ParseSamplePolicy(tail) should fail")
+	}
+}
@@ -0,0 +1,142 @@
+package ecg
+
+import (
+	"path"
+	"strings"
+)
+
+// divergentSections returns path scoped sections for the subdirectories whose confident properties differ from what
+// their parent directory ends up with. Scopes must be ordered so that parents come before their children.
+func divergentSections(scopes []*scope, minFiles int) []*Section {
+	// effective values for each scope, keyed by format and header
+	effective := map[string]map[string]map[string]string{}
+	var result []*Section
+	for _, s := range scopes {
+		values := map[string]map[string]string{}
+		effective[s.path] = values
+		parent := effective[parentScope(effective, s.path)]
+		for _, section := range s.sections {
+			key := section.Format + " " + section.Header()
+			if s.path == "/" {
+				values[key] = section.Values()
+				continue
+			}
+			inherited := parent[key]
+			values[key] = map[string]string{}
+			for k, v := range inherited {
+				values[key][k] = v
+			}
+			if section.Surveyor == nil || section.Surveyor.Files < minFiles {
+				continue
+			}
+			var properties []*Property
+			for _, p := range section.Properties {
+				if p.Key == "" || p.Confidence < divergenceConfidence {
+					continue
+				}
+				if v, ok := inherited[p.Key]; ok && v == p.Value {
+					continue
+				}
+				properties = append(properties, p)
+				values[key][p.Key] = p.Value
+			}
+			if len(properties) == 0 {
+				continue
+			}
+			globs := make([]string, 0, len(section.FileGlobs))
+			for _, g := range section.FileGlobs {
+				globs = append(globs, ScopeGlob(s.path, g))
+			}
+			result = append(result, &Section{
+				Format:     section.Format,
+				FileGlobs:  globs,
+				Path:       s.path,
+				Confidence: section.Confidence,
+				Properties: append(properties, &Property{}),
+				Surveyor:   section.Surveyor,
+				Result:     section.Result,
+			})
+		}
+	}
+	return result
+}
+
+// parentScope the closest ancestor of p which has been processed
+func parentScope(processed map[string]map[string]map[string]string, p string) string {
+	for p != "/" {
+		p = path.Dir(p)
+		if _, ok := processed[p]; ok {
+			return p
+		}
+	}
+	return p
+}
+
+// pathDepth how many directories deep the slash separated path is, "." is 0
+func pathDepth(p string) int {
+	if p == "." || p == "" {
+		return 0
+	}
+	return strings.Count(p, "/") + 1
+}
+
+// ScopeGlob makes a glob from a root section apply only within the directory, ie `*.ts` in `/frontend` becomes
+// `frontend/**.ts`. Globs from ExactGlob already only match one file and are left as they are.
+func ScopeGlob(dir, glob string) string {
+	dir = strings.Trim(dir, "/")
+	if dir == "" || strings.HasPrefix(glob, "/") {
+		return glob
+	}
+	if strings.HasPrefix(glob, "*") {
+		return dir + "/*" + glob
+	}
+	return dir + "/**/" + glob
+}
+
+// UnscopeGlob reverses ScopeGlob, globs from ExactGlob are made relative to the directory
+func UnscopeGlob(dir, glob string) string {
+	dir = strings.Trim(dir, "/")
+	if dir == "" {
+		return glob
+	}
+	if rest, ok := strings.CutPrefix(glob, "/"+dir+"/"); ok {
+		return "/" + rest
+	}
+	if rest, ok := strings.CutPrefix(glob, dir+"/**/"); ok {
+		return rest
+	}
+	if rest, ok := strings.CutPrefix(glob, dir+"/*"); ok {
+		return rest
+	}
+	return glob
+}
+
+// Split divides the guess into one guess per directory for writing nested .editorconfig files, keyed by the
+// directory, "/" holds the root file
+func (g *Guess) Split() map[string]*Guess {
+	result := map[string]*Guess{
+		"/": {Preamble: g.Preamble},
+	}
+	for _, s := range g.Sections {
+		p := s.Path
+		if p == "" {
+			p = "/"
+		}
+		sub, ok := result[p]
+		if !ok {
+			sub = &Guess{}
+			result[p] = sub
+		}
+		if p == "/" {
+			sub.Sections = append(sub.Sections, s)
+			continue
+		}
+		unscoped := *s
+		unscoped.FileGlobs = make([]string, 0, len(s.FileGlobs))
+		for _, glob := range s.FileGlobs {
+			unscoped.FileGlobs = append(unscoped.FileGlobs, UnscopeGlob(p, glob))
+		}
+		sub.Sections = append(sub.Sections, &unscoped)
+	}
+	return result
+}
@@ -0,0 +1,69 @@
+package ecg
+
+import "fmt"
+
+// Thresholds the cut-offs a BasicSurveyor decides properties with, see DefaultThresholds and WithThresholds
+type Thresholds struct {
+	// Share of files which have to agree on insert_final_newline
+	FinalNewline float64 `json:"final_newline" yaml:"final_newline"`
+	// Share of files which have to keep trailing whitespace out for trim_trailing_whitespace = true, as many have to
+	// keep it for false
+	TrailingWhitespace float64 `json:"trailing_whitespace" yaml:"trailing_whitespace"`
+	// Share of the lines in a file, and then of the files, which have to agree on end_of_line
+	LineEndings float64 `json:"line_endings" yaml:"line_endings"`
+	// Share of lines which have to be indented with tabs for indent_style = tab, as many have to use spaces for space
+	IndentStyle float64 `json:"indent_style" yaml:"indent_style"`
+	// max_line_length is guessed in steps of this many columns
+	LineLengthStep int `json:"line_length_step" yaml:"line_length_step"`
+	// The shortest max_line_length guessed for tab indented files
+	LineLengthGoal int `json:"line_length_goal" yaml:"line_length_goal"`
+	// A file with more distinct trailing whitespace than this is counted as keeping it
+	TrailingWhitespaceCommon int `json:"trailing_whitespace_common" yaml:"trailing_whitespace_common"`
+	// How many files an extension no format covers needs before it gets a section of its own
+	ExtensionMinFiles int `json:"extension_min_files" yaml:"extension_min_files"`
+}
+
+// DefaultThresholds the thresholds used unless WithThresholds says otherwise
+func DefaultThresholds() Thresholds {
+	return Thresholds{
+		FinalNewline:             0.8,
+		TrailingWhitespace:       0.8,
+		LineEndings:              0.8,
+		IndentStyle:              0.8,
+		LineLengthStep:           lineLengthStep,
+		LineLengthGoal:           80,
+		TrailingWhitespaceCommon: 5,
+		ExtensionMinFiles:        3,
+	}
+}
+
+// Validate reports the first threshold which can't work, shares have to be over half so both sides of a property can't
+// be true at once
+func (t Thresholds) Validate() error {
+	for _, share := range []struct {
+		name  string
+		value float64
+	}{
+		{"final_newline", t.FinalNewline},
+		{"trailing_whitespace", t.TrailingWhitespace},
+		{"line_endings", t.LineEndings},
+		{"indent_style", t.IndentStyle},
+	} {
+		if share.value <= 0.5 || share.value > 1 {
+			return fmt.Errorf("threshold %s is %v, it has to be over 0.5 and at most 1", share.name, share.value)
+		}
+	}
+	if t.LineLengthStep <= 0 {
+		return fmt.Errorf("threshold line_length_step is %d, it has to be over 0", t.LineLengthStep)
+	}
+	if t.LineLengthGoal < t.LineLengthStep {
+		return fmt.Errorf("threshold line_length_goal is %d, it has to be at least line_length_step", t.LineLengthGoal)
+	}
+	if t.TrailingWhitespaceCommon < 0 {
+		return fmt.Errorf("threshold trailing_whitespace_common is %d, it can't be negative", t.TrailingWhitespaceCommon)
+	}
+	if t.ExtensionMinFiles < 1 {
+		return fmt.Errorf("threshold extension_min_files is %d, it has to be at least 1", t.ExtensionMinFiles)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package ecg_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	ecg "editorconfig-guesser"
+	_ "editorconfig-guesser/fileformats"
+)
+
+func TestThresholds_Validate(t *testing.T) {
+	if err := ecg.DefaultThresholds().Validate(); err != nil {
+		t.Errorf("DefaultThresholds().Validate() = %v", err)
+	}
+	for name, change := range map[string]func(*ecg.Thresholds){
+		"half":      func(t *ecg.Thresholds) { t.IndentStyle = 0.5 },
+		"over one":  func(t *ecg.Thresholds) { t.FinalNewline = 1.1 },
+		"step":      func(t *ecg.Thresholds) { t.LineLengthStep = 0 },
+		"goal":      func(t *ecg.Thresholds) { t.LineLengthGoal = 10 },
+		"common":    func(t *ecg.Thresholds) { t.TrailingWhitespaceCommon = -1 },
+		"min files": func(t *ecg.Thresholds) { t.ExtensionMinFiles = 0 },
+	} {
+		th := ecg.DefaultThresholds()
+		change(&th)
+		if err := th.Validate(); err == nil {
+			t.Errorf("%s: Validate() succeeded, want an error", name)
+		}
+	}
+}
+
+func TestWithThresholds(t *testing.T) {
+	dir := fstest.MapFS{
+		"a.go": &fstest.MapFile{Data: []byte("package a\n")},
+		"b.go": &fstest.MapFile{Data: []byte("package b\n")},
+		"c.go": &fstest.MapFile{Data: []byte("package c")},
+	}
+	ignore := func(f *ecg.File) bool { return false }
+	finalNewline := func(opts ...ecg.Option) string {
+		guess, err := ecg.Analyze(dir, ignore, append(opts, ecg.WithOnlyFormats("all files"))...)
+		if err != nil {
+			t.Fatalf("Analyze failed: %v", err)
+		}
+		if p := guess.Section("*").Get("insert_final_newline"); p != nil {
+			return p.Value
+		}
+		return ""
+	}
+	if got := finalNewline(); got != "" {
+		t.Errorf("insert_final_newline = %q with 2 of 3 files, want it left out", got)
+	}
+	th := ecg.DefaultThresholds()
+	th.FinalNewline = 0.6
+	if got := finalNewline(ecg.WithThresholds(th)); got != "true" {
+		t.Errorf("insert_final_newline = %q with a 0.6 threshold, want true", got)
+	}
+	th.FinalNewline = 0.4
+	if _, err := ecg.Analyze(dir, ignore, ecg.WithThresholds(th)); err == nil {
+		t.Error("Analyze() with a 0.4 threshold succeeded, want an error")
+	}
+}
+
+func TestWithFormats(t *testing.T) {
+	dir := fstest.MapFS{
+		"main.go":  &fstest.MapFile{Data: []byte("package main\n")},
+		"index.ts": &fstest.MapFile{Data: []byte("start()\n")},
+	}
+	ignore := func(f *ecg.File) bool { return false }
+	guess, err := ecg.Analyze(dir, ignore, ecg.WithoutFormats("typescript"), ecg.WithoutConsolidation())
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	for _, s := range guess.Sections {
+		if s.Format == "TypeScript" {
+			t.Errorf("Analyze() ran TypeScript which was left out")
+		}
+	}
+	guess, err = ecg.Analyze(dir, ignore, ecg.WithOnlyFormats("TypeScript"), ecg.WithoutConsolidation())
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if len(guess.Sections) != 1 || guess.Sections[0].Format != "TypeScript" {
+		t.Errorf("Analyze() with only TypeScript = \n%s", guess)
+	}
+	if _, err := ecg.Analyze(dir, ignore, ecg.WithOnlyFormats("Cobol")); err == nil {
+		t.Error("Analyze() with an unknown format succeeded, want an error")
+	}
+}
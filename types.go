@@ -2,23 +2,17 @@
 package ecg
 
 import (
-	"errors"
 	"fmt"
-	"github.com/alecthomas/units"
-	"github.com/gabriel-vasile/mimetype"
-	"github.com/saintfish/chardet"
 	"golang.org/x/exp/maps"
-		"io"
-	"io/fs"
-	"log"
 	"math"
-	"os"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 )
 
-// Contraster Provides contrasts two SummaryResults from the same file format for the purpose of consolidation -- future -- maybe
+// Contraster contrasts two SummaryResults for the purpose of consolidation, 0 means they are equivalent and can share a
+// section. See PropertiesContraster
 type Contraster func(s1, s2 *SummaryResult) int
 
 // ErrorStringer ...
@@ -30,9 +24,10 @@ type ErrorStringer interface {
 type SummaryResult struct {
 	// Impacted globs, used for reconstructing.
 	FileGlobs []string
-	// How confident we are not used atm
+	// How confident the format is in the result (0 to 1)
 	Confidence float64
-	// Future use to compare how different two file paths (same file format) are
+	// Compares this result with another during consolidation once their sections have the same properties, nil when
+	// those are enough
 	Contaster Contraster
 	// The template
 	Template ErrorStringer
@@ -40,71 +35,10 @@ type SummaryResult struct {
 	Path string
 	// Internal data, probably going to be used by Contraster
 	Data any
-}
-
-// File reference, could also be a cache
-type File struct {
-	Filename   string
-	FileOpener fs.FS
-	size       *int64
-	sync.Mutex
-}
-
-// Size of file + cache
-func (fd *File) Size() int64 {
-	fd.Lock()
-	defer fd.Unlock()
-	if fd.size != nil {
-		return *fd.size
-	}
-	st, err := os.Stat(fd.Filename)
-	if err != nil {
-		return -1
-	}
-	s := st.Size()
-	fd.size = &s
-	return *fd.size
-}
-
-// Open abstracter eventually might cache, perhaps checking file size first - or only caching the first 256kb
-func (fd *File) Open() (io.ReadSeekCloser, error) {
-	if fd.FileOpener != nil {
-		f, err := fd.FileOpener.Open(fd.Filename)
-		rsc, ok := f.(io.ReadSeekCloser)
-		if !ok {
-			return nil, fmt.Errorf("file isn't readable, seakable or closable")
-		}
-		return rsc, err
-	}
-	return os.Open(fd.Filename)
-}
-
-// IsBinary ...
-func (fd *File) IsBinary() bool {
-	fh, err := fd.Open()
-	if err != nil {
-		return false // So something else can generate the error TODO
-	}
-	defer func() {
-		if cerr := fh.Close(); cerr != nil {
-			log.Printf("Error: closing %s: %s", fd.Filename, cerr)
-		}
-	}()
-	testBytes := make([]byte, 1*units.KiB)
-	n, err := fh.Read(testBytes)
-	if err != nil {
-		return false
-	}
-	testBytes = testBytes[:n]
-	detectedMIME := mimetype.Detect(testBytes)
-
-	isBinary := true
-	for mtype := detectedMIME; mtype != nil; mtype = mtype.Parent() {
-		if mtype.Is("text/plain") {
-			isBinary = false
-		}
-	}
-	return isBinary
+	// The statistics behind the template, nil for formats which don't survey the files (ie Presence)
+	Surveyor *BasicSurveyor
+	// What the format noticed about the files which doesn't belong in the section, see Guess.Warnings
+	Warnings []*Warning
 }
 
 // FileFormat a file format
@@ -144,85 +78,78 @@ type BasicSurveyor struct {
 	MaxLineLength      string
 	TabWidth           string
 	whitespacePrefixes map[string]int
+	// Confidence of each property Summarize inferred (0 to 1) keyed by the editorconfig property name
+	Confidence map[string]float64
+	// What each file read points to, see Dissents
+	Votes []*FileVote
+	// Guards the statistics so a surveyor can be shared between goroutines
+	mu sync.Mutex
+	// See SetThresholds, nil for DefaultThresholds
+	thresholds *Thresholds
+	// Properties the language decides rather than the files, see Mandate
+	mandates map[string]string
 }
 
 // NewBasicSurveyor ...
 func NewBasicSurveyor() *BasicSurveyor {
 	return &BasicSurveyor{
 		CharacterSets: &CharSetSummary{
-			Sets: map[string]int{},
+			Sets:       map[string]int{},
+			Confidence: map[string]int{},
 		},
 		whitespacePrefixes: map[string]int{},
 		lineLengths:        map[LineLengthDetail]int{},
+		Confidence:         map[string]float64{},
 	}
 }
 
-// ReadFile ...
+// ReadFile adds the file to the survey, it can be called from several goroutines at once. The file is only read once
+// however many surveyors it is given to, see File.Survey
 func (l *BasicSurveyor) ReadFile(fd *File) (string, bool, *LineSurvey, error) {
+	result, err := fd.Survey()
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	l.Files++
-	f, err := fd.Open()
 	if err != nil {
-		return "", false, nil, fmt.Errorf("opening %s: %w", fd.Filename, err)
+		return "", false, nil, err
 	}
-	defer func() {
-		if err := f.Close(); err != nil {
-			log.Printf("Error: closing %s: %s", fd.Filename, err)
-		}
-	}()
-	b := make([]byte, ReadSize)
-	if n, err := f.Read(b); err != nil {
-		return "", false, nil, fmt.Errorf("read %d (of %d) from %s: %w", n, len(b), fd.Filename, err)
-	} else {
-		b = b[:n]
+	t := l.limits()
+	charset := result.Charset
+	if result.BOM {
+		l.CharacterSets.Boms++
 	}
-	detector := chardet.NewTextDetector()
-	result, err := detector.DetectBest(b)
-	if err != nil {
-		if !errors.Is(err, chardet.NotDetectedError) {
-			return "", false, nil, fmt.Errorf("detect character encoding from %s: %w", fd.Filename, err)
-		}
-	}
-	var charset string
-	if result != nil && result.Confidence > 80 {
-		// TODO use with Summary's Confidence
-		charset = result.Charset
-	}
-	var finalNewLine bool
-	survey := LineSurveySample(b)
-	if n, err := f.Seek(-1, io.SeekEnd); err != nil {
-		return "", false, nil, fmt.Errorf("seak %d from %s: %w", n, fd.Filename, err)
-	} else if n > 0 {
-		b = make([]byte, 1)
-		if n, err := f.Read(b); err != nil {
-			return "", false, nil, fmt.Errorf("read %d (of %d) from %s: %w", n, len(b), fd.Filename, err)
-		}
-		finalNewLine = n > 0 && b[0] == '\n'
+	if result.ASCII {
+		l.CharacterSets.ASCII++
 	}
 	switch charset {
-	case "UTF-8":
+	case CharsetUTF8BOM:
+		l.CharacterSets.Utf8Bom++
+	case "utf-8":
 		l.CharacterSets.Utf8++
-	case "UTF-16BE":
+	case "utf-16be":
 		l.CharacterSets.Utf16Be++
-	case "UTF-16LE":
+	case "utf-16le":
 		l.CharacterSets.Utf16Le++
-	case "ISO-8859-1":
+	case "latin1":
 		l.CharacterSets.Latin1++
 	case "":
 	default:
 		l.CharacterSets.OtherTotal++
 	}
 	l.CharacterSets.Sets[charset]++
+	l.CharacterSets.Confidence[charset] += result.CharsetConfidence
+	finalNewLine, survey := result.FinalNewLine, result.Lines
 	if finalNewLine {
 		l.finalNewLineBalance.True++
 	} else {
 		l.finalNewLineBalance.False++
 	}
-	if survey.LinuxNewlinesPercent() >= .80 { // 20% threshold
+	if survey.LinuxNewlinesPercent() >= t.LineEndings {
 		l.lineEndings.Unix++
-	} else if survey.WindowNewlinesPercent() >= .8 { // 80% threshold
+	} else if survey.WindowNewlinesPercent() >= t.LineEndings {
 		l.lineEndings.Windows++
 	}
-	if !survey.TrailingWhitespaceCommon() {
+	if !survey.trailingWhitespaceCommon(t.TrailingWhitespaceCommon) {
 		l.trailingSpaceOkay.True++
 	} else {
 		l.trailingSpaceOkay.False++
@@ -233,37 +160,145 @@ func (l *BasicSurveyor) ReadFile(fd *File) (string, bool, *LineSurvey, error) {
 	for k, v := range survey.WhitespacePrefix {
 		l.whitespacePrefixes[k] += v
 	}
+	l.Votes = append(l.Votes, newFileVote(fd.Filename, charset, finalNewLine, survey, t))
 	return charset, finalNewLine, survey, nil
 }
 
 // Summarize ...
 func (l *BasicSurveyor) Summarize() {
-	if l.FinalNewLineBalanceTruePercent() > .80 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.Confidence = map[string]float64{}
+	t := l.limits()
+	sample := SampleConfidence(l.Files)
+	if v := l.FinalNewLineBalanceTruePercent(); v > t.FinalNewline {
 		l.InsertFinalNewline = "true"
-	} else if l.FinalNewLineBalanceFalsePercent() > .80 {
+		l.Confidence["insert_final_newline"] = v * sample
+	} else if v := l.FinalNewLineBalanceFalsePercent(); v > t.FinalNewline {
 		l.InsertFinalNewline = "false"
+		l.Confidence["insert_final_newline"] = v * sample
 	}
 	l.Charset = l.CharacterSets.BestFit()
 	l.Charsets = l.CharacterSets.Distribution(l.Files)
-	if l.TrailingSpaceOkayPercent() >= .80 { // 20% threshold
+	if _, ok := EditorConfigCharset(l.Charset); !ok && l.Charset != "" {
+		l.Charsets = fmt.Sprintf("%s isn't a charset editorconfig supports: %s", l.Charset, l.Charsets)
+		l.Charset = ""
+	}
+	if l.Charset != "" && l.Files > 0 {
+		l.Confidence["charset"] = float64(l.CharacterSets.Compatible(l.Charset)) / float64(l.Files) * l.CharacterSets.DetectorConfidence(l.Charset) * sample
+	}
+	if v := l.TrailingSpaceOkayPercent(); v >= t.TrailingWhitespace {
 		l.TrimTrailingWhitespace = "true"
-	} else if l.TrailingSpaceOkayPercent() <= .20 { // 20% threshold
+		l.Confidence["trim_trailing_whitespace"] = v * sample
+	} else if v <= 1-t.TrailingWhitespace {
 		l.TrimTrailingWhitespace = "false"
+		l.Confidence["trim_trailing_whitespace"] = (1 - v) * sample
 	}
-	if l.UnixLineEndingPercent() >= .80 {
+	if v := l.UnixLineEndingPercent(); v >= t.LineEndings {
 		l.EndOfLine = "lf"
-	} else if l.WindowsLineEndingPercent() >= .80 {
+		l.Confidence["end_of_line"] = v * sample
+	} else if v := l.WindowsLineEndingPercent(); v >= t.LineEndings {
 		l.EndOfLine = "crlf"
+		l.Confidence["end_of_line"] = v * sample
 	}
 	// TODO think about mixed cases
-	if v := l.TabPercent(); v >= .8 {
+	if v := l.TabPercent(); v >= t.IndentStyle {
 		l.IndentStyle = "tabs"
+		l.Confidence["indent_style"] = v * sample
 		l.TabWidth, l.MaxLineLength = l.TabWidthLineLengthCalc()
-	} else if v <= .2 {
+		if l.TabWidth != "" {
+			l.Confidence["tab_width"] = v * sample
+		}
+	} else if v <= 1-t.IndentStyle {
 		l.IndentStyle = "spaces"
+		l.Confidence["indent_style"] = (1 - v) * sample
 		l.MaxLineLength = l.SpaceMaxLineLengthCalc()
 	}
-	l.IndentSize = l.IndentSizeCalc()
+	if l.MaxLineLength != "" {
+		l.Confidence["max_line_length"] = l.maxLineLengthConfidence(l.MaxLineLength)
+	}
+	var indentSizeShare float64
+	l.IndentSize, indentSizeShare = l.indentSizeCalc()
+	if l.IndentSize != "" {
+		l.Confidence["indent_size"] = indentSizeShare * sample
+	}
+	for key := range l.mandates {
+		l.Confidence[key] = 1
+	}
+}
+
+// Mandate sets a property the language requires whatever the files look like, ie gofmt's tabs. The files are held to
+// it rather than to the majority, see Dissents, and it is always certain.
+func (l *BasicSurveyor) Mandate(key, value string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.mandates == nil {
+		l.mandates = map[string]string{}
+	}
+	l.mandates[key] = value
+}
+
+// SetThresholds decides properties with t instead of DefaultThresholds
+func (l *BasicSurveyor) SetThresholds(t Thresholds) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.thresholds = &t
+}
+
+// limits the thresholds set with SetThresholds, DefaultThresholds if there are none
+func (l *BasicSurveyor) limits() Thresholds {
+	if l.thresholds != nil {
+		return *l.thresholds
+	}
+	return DefaultThresholds()
+}
+
+// SampleConfidence how much a vote from n samples can be trusted, it approaches 1 as n grows
+func SampleConfidence(n int) float64 {
+	if n <= 0 {
+		return 0
+	}
+	return float64(n) / float64(n+1)
+}
+
+// OverallConfidence the mean confidence of the properties Summarize inferred
+func (l *BasicSurveyor) OverallConfidence() float64 {
+	if len(l.Confidence) == 0 {
+		return 0
+	}
+	total := 0.0
+	for _, v := range l.Confidence {
+		total += v
+	}
+	return total / float64(len(l.Confidence))
+}
+
+// maxLineLengthConfidence how well the lines support the maximum line length, lines within a step of it support it
+// while lines longer than it contradict it
+func (l *BasicSurveyor) maxLineLengthConfidence(maxLineLength string) float64 {
+	maximum, err := strconv.Atoi(maxLineLength)
+	if err != nil {
+		return 0
+	}
+	tabWidth, err := strconv.Atoi(l.TabWidth)
+	if err != nil {
+		tabWidth = 8
+	}
+	near, longer, total := 0, 0, 0
+	for k, v := range l.lineLengths {
+		length := k.length + k.tabIndentation*tabWidth
+		switch {
+		case length > maximum:
+			longer += v
+		case length > maximum-l.limits().LineLengthStep:
+			near += v
+		}
+		total += v
+	}
+	if total == 0 {
+		return 0
+	}
+	return SampleConfidence(near) * (1 - float64(longer)/float64(total))
 }
 
 // WindowsLineEndingPercent ...
@@ -354,10 +389,9 @@ func (l *BasicSurveyor) TabWidthLineLengthCalc() (string, string) {
 			DepthCount: map[int]int{},
 		}
 	}
-	const step = 20
-	const firstGoal = 80
-	const minimum = firstGoal - step
-	const minimumDepth = minimum / step
+	step := l.limits().LineLengthStep
+	minimum := l.limits().LineLengthGoal - step
+	minimumDepth := minimum / step
 	for k, v := range l.lineLengths {
 		for _, dk := range depthKeys {
 			l := k.length + k.tabIndentation*dk
@@ -396,12 +430,18 @@ func (l *BasicSurveyor) TabWidthLineLengthCalc() (string, string) {
 // SpaceMaxLineLengthCalc ...
 func (l *BasicSurveyor) SpaceMaxLineLengthCalc() string {
 	// TODO skip tab stuff
-	d, _ := l.TabWidthLineLengthCalc()
-	return d
+	_, maxLineLength := l.TabWidthLineLengthCalc()
+	return maxLineLength
 }
 
 // IndentSizeCalc ...
 func (l *BasicSurveyor) IndentSizeCalc() string {
+	indentSize, _ := l.indentSizeCalc()
+	return indentSize
+}
+
+// indentSizeCalc returns the indent size and the share of indented lines which are a multiple of it
+func (l *BasicSurveyor) indentSizeCalc() (string, float64) {
 	all := maps.Keys(l.whitespacePrefixes)
 	sort.Strings(all)
 	longest := 0
@@ -442,9 +482,64 @@ func (l *BasicSurveyor) IndentSizeCalc() string {
 		}
 	}
 	if len(longestRun.RunStr) == 0 {
-		return ""
+		return "", 0
+	}
+	indented := 0
+	multiples := 0
+	for k, v := range l.whitespacePrefixes {
+		if len(k) == 0 {
+			continue
+		}
+		indented += v
+		if len(k)%len(longestRun.RunStr) == 0 {
+			multiples += v
+		}
+	}
+	return fmt.Sprintf("%d", len(longestRun.RunStr)), float64(multiples) / float64(indented)
+}
+
+// Delta a surveyor with only the properties which differ from parent, so a section leaves out what its parent section
+// already says. A nil parent returns the surveyor itself.
+func (l *BasicSurveyor) Delta(parent *BasicSurveyor) *BasicSurveyor {
+	if parent == nil {
+		return l
+	}
+	result := NewBasicSurveyor()
+	if parent.InsertFinalNewline != l.InsertFinalNewline {
+		result.InsertFinalNewline = l.InsertFinalNewline
+	}
+	if parent.Charset != l.Charset {
+		result.Charset = l.Charset
+		result.Charsets = l.Charsets
+	} else if l.Charset == "" && parent.Charsets != l.Charsets {
+		// Both undecided, the distribution is all there is to tell them apart
+		result.Charsets = l.Charsets
+	}
+	if parent.TrimTrailingWhitespace != l.TrimTrailingWhitespace {
+		result.TrimTrailingWhitespace = l.TrimTrailingWhitespace
+	}
+	if parent.EndOfLine != l.EndOfLine {
+		result.EndOfLine = l.EndOfLine
+	}
+	if parent.Files != l.Files {
+		result.Files = l.Files
 	}
-	return fmt.Sprintf("%d", len(longestRun.RunStr))
+	if parent.CharacterSets != l.CharacterSets {
+		result.CharacterSets = l.CharacterSets
+	}
+	if parent.IndentStyle != l.IndentStyle {
+		result.IndentStyle = l.IndentStyle
+	}
+	if parent.IndentSize != l.IndentSize {
+		result.IndentSize = l.IndentSize
+	}
+	if parent.MaxLineLength != l.MaxLineLength {
+		result.MaxLineLength = l.MaxLineLength
+	}
+	if parent.TabWidth != l.TabWidth {
+		result.TabWidth = l.TabWidth
+	}
+	return result
 }
 
 // BasicSurveyorGetter ...
@@ -456,3 +551,21 @@ type BasicSurveyorGetter interface {
 type BasicSurveyorSetter interface {
 	SetBasicSurveyor(af *BasicSurveyor)
 }
+
+// FileClaimer a FileRunner which can say whether it surveys a file, the Generic format leaves those files to it. It is
+// called from other goroutines so it mustn't change anything.
+type FileClaimer interface {
+	Claims(f *File) bool
+}
+
+// ThresholdsSetter a FileRunner which decides with thresholds, it is given those of the analysis, see WithThresholds,
+// before any file is sent to it. It hands them on to its BasicSurveyors.
+type ThresholdsSetter interface {
+	SetThresholds(t Thresholds)
+}
+
+// FileFormatsSetter a FileRunner which is given every FileFormat created alongside it, ie to find a parent format. The
+// other formats have all finished by the time the templates are rendered.
+type FileFormatsSetter interface {
+	SetFileFormats(ffs []FileFormat)
+}
@@ -1,6 +1,12 @@
 package ecg
 
-import "testing"
+import (
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+	"testing/fstest"
+)
 
 func TestBasicSurveyor_TabWidthLineLengthCalc(t *testing.T) {
 	tests := []struct {
@@ -95,6 +101,8 @@ func TestBasicSurveyor_IndentSizeCalc(t *testing.T) {
 		name           string
 		BasicSurveyor  *BasicSurveyor
 		wantindentSize string
+		// Of the indented lines, those which are a multiple of the indent size
+		wantShare float64
 	}{
 		{
 			name: "empty",
@@ -115,6 +123,7 @@ func TestBasicSurveyor_IndentSizeCalc(t *testing.T) {
 				},
 			},
 			wantindentSize: "2",
+			wantShare:      1,
 		},
 		{
 			name: "Double space - misleading single",
@@ -129,6 +138,7 @@ func TestBasicSurveyor_IndentSizeCalc(t *testing.T) {
 				},
 			},
 			wantindentSize: "2",
+			wantShare:      20.0 / 21,
 		},
 	}
 	for _, tt := range tests {
@@ -136,6 +146,92 @@ func TestBasicSurveyor_IndentSizeCalc(t *testing.T) {
 			if indentSize := tt.BasicSurveyor.IndentSizeCalc(); indentSize != tt.wantindentSize {
 				t.Errorf("IndentSizeCalc() = %v, want %v", indentSize, tt.wantindentSize)
 			}
+			if _, share := tt.BasicSurveyor.indentSizeCalc(); math.Abs(share-tt.wantShare) > 0.0001 {
+				t.Errorf("indentSizeCalc() share = %v, want %v", share, tt.wantShare)
+			}
+		})
+	}
+}
+
+func TestBasicSurveyor_SummarizeConfidence(t *testing.T) {
+	l := NewBasicSurveyor()
+	l.Files = 4
+	l.finalNewLineBalance.True = 4
+	l.lineEndings.Unix = 3
+	l.lineEndings.Windows = 1
+	l.trailingSpaceOkay.True = 4
+	l.whitespacePrefixes = map[string]int{"": 10, " ": 1, "  ": 3, "    ": 7, "      ": 3, "        ": 7}
+	l.Summarize()
+	tests := []struct {
+		property string
+		want     float64
+	}{
+		{property: "insert_final_newline", want: 0.8},
+		{property: "end_of_line", want: 0},
+		{property: "trim_trailing_whitespace", want: 0.8},
+		{property: "indent_size", want: 0.8 * 20 / 21},
+	}
+	for _, tt := range tests {
+		t.Run(tt.property, func(t *testing.T) {
+			if got := l.Confidence[tt.property]; math.Abs(got-tt.want) > 0.0001 {
+				t.Errorf("Confidence[%s] = %v, want %v", tt.property, got, tt.want)
+			}
 		})
 	}
 }
+
+func TestBasicSurveyor_ReadFileBOM(t *testing.T) {
+	bom := "\xEF\xBB\xBF"
+	mapFS := fstest.MapFS{
+		"a.cs":  &fstest.MapFile{Data: []byte(bom + "class A {}\r\n")},
+		"b.cs":  &fstest.MapFile{Data: []byte(bom + "class B {}\r\n")},
+		"c.cs":  &fstest.MapFile{Data: []byte(bom + "// Ünïcödé ümläüts äré fün\r\nclass C {}\r\n")},
+		"d.cs":  &fstest.MapFile{Data: []byte("// Ünïcödé ümläüts äré fün, nö BÖM hérë\r\nclass D {}\r\n")},
+		"e.txt": &fstest.MapFile{Data: []byte("\xFF\xFEa\x00\n\x00")},
+		"f.txt": &fstest.MapFile{Data: []byte("\xFF\xFE\x00\x00a\x00\x00\x00")},
+	}
+	l := NewBasicSurveyor()
+	for _, fn := range []string{"a.cs", "b.cs", "c.cs", "d.cs"} {
+		if _, _, survey, err := l.ReadFile(&File{Filename: fn, FileOpener: mapFS}); err != nil {
+			t.Fatalf("ReadFile(%s) error = %v", fn, err)
+		} else if fn == "a.cs" && survey.LineLengths[LineLengthDetail{length: 10}] != 1 {
+			t.Errorf("ReadFile(%s) counted the byte order mark as part of the line: %v", fn, survey.LineLengths)
+		}
+	}
+	l.Summarize()
+	if l.Charset != CharsetUTF8BOM || l.CharacterSets.Utf8Bom != 3 || l.CharacterSets.Boms != 3 {
+		t.Errorf("Charset = %s with %d utf-8-bom and %d BOMs, want utf-8-bom with 3 and 3", l.Charset, l.CharacterSets.Utf8Bom, l.CharacterSets.Boms)
+	}
+	dissents := l.Dissents()
+	if len(dissents) != 1 || dissents[0].Filename != "d.cs" || dissents[0].Property != "charset" {
+		t.Errorf("Dissents() = %+v, want d.cs charset", dissents)
+	}
+	for fn, want := range map[string]string{"e.txt": "utf-16le", "f.txt": "UTF-32LE"} {
+		if charset, _, _, err := NewBasicSurveyor().ReadFile(&File{Filename: fn, FileOpener: mapFS}); err != nil || charset != want {
+			t.Errorf("ReadFile(%s) = %s, %v; want %s", fn, charset, err, want)
+		}
+	}
+}
+
+func TestBasicSurveyor_ReadFileConcurrent(t *testing.T) {
+	mapFS := fstest.MapFS{}
+	for i := 0; i < 50; i++ {
+		mapFS[fmt.Sprintf("%d.txt", i)] = &fstest.MapFile{Data: []byte("a\n\tb\n")}
+	}
+	l := NewBasicSurveyor()
+	wg := &sync.WaitGroup{}
+	for fn := range mapFS {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, _, _, err := l.ReadFile(&File{Filename: fn, FileOpener: mapFS}); err != nil {
+				t.Errorf("ReadFile(%s) error = %v", fn, err)
+			}
+		}()
+	}
+	wg.Wait()
+	l.Summarize()
+	if l.Files != 50 || len(l.Votes) != 50 || l.EndOfLine != "lf" {
+		t.Errorf("got %d files, %d votes and end_of_line %q; want 50, 50 and lf", l.Files, len(l.Votes), l.EndOfLine)
+	}
+}
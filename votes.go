@@ -0,0 +1,120 @@
+package ecg
+
+import (
+	"sort"
+)
+
+// FileVote the values a single file points to, keyed by the editorconfig property name. Properties the file has no
+// opinion on, ie indent_style for a file without indentation, are left out.
+type FileVote struct {
+	Filename string
+	Values   map[string]string
+}
+
+// Dissent a file which points to a different value than the one the majority of the files settled on
+type Dissent struct {
+	Filename string `json:"filename" yaml:"filename"`
+	Property string `json:"property" yaml:"property"`
+	// What the file points to
+	Value string `json:"value" yaml:"value"`
+	// What the majority settled on
+	Majority string `json:"majority" yaml:"majority"`
+}
+
+// newFileVote the votes of a single file from its survey
+func newFileVote(filename, charset string, finalNewLine bool, survey *LineSurvey, t Thresholds) *FileVote {
+	v := &FileVote{
+		Filename: filename,
+		Values: map[string]string{
+			"insert_final_newline":     "false",
+			"trim_trailing_whitespace": "true",
+		},
+	}
+	if finalNewLine {
+		v.Values["insert_final_newline"] = "true"
+	}
+	if survey.trailingWhitespaceCommon(t.TrailingWhitespaceCommon) {
+		v.Values["trim_trailing_whitespace"] = "false"
+	}
+	if charset != "" {
+		v.Values["charset"] = charset
+	}
+	if survey.NewLines > 0 {
+		if survey.LinuxNewlinesPercent() >= t.LineEndings {
+			v.Values["end_of_line"] = "lf"
+		} else if survey.WindowNewlinesPercent() >= t.LineEndings {
+			v.Values["end_of_line"] = "crlf"
+		} else {
+			v.Values["end_of_line"] = "mixed"
+		}
+	}
+	tabs, spaces := survey.IndentedWith('\t'), survey.IndentedWith(' ')
+	if tabs > spaces {
+		v.Values["indent_style"] = "tab"
+	} else if spaces > 0 {
+		v.Values["indent_style"] = "space"
+	}
+	return v
+}
+
+// majority the values Summarize settled on for the properties files vote on, mandated values take their place
+func (l *BasicSurveyor) majority() map[string]string {
+	result := map[string]string{
+		"insert_final_newline":     l.InsertFinalNewline,
+		"trim_trailing_whitespace": l.TrimTrailingWhitespace,
+		"charset":                  l.Charset,
+		"end_of_line":              l.EndOfLine,
+		"indent_style":             l.IndentStyle,
+	}
+	for key, value := range l.mandates {
+		if _, ok := result[key]; ok {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+// Dissents the files which point to a different value than Summarize settled on, sorted by file then property.
+// Properties without a majority are left out.
+func (l *BasicSurveyor) Dissents() []*Dissent {
+	var result []*Dissent
+	for property, majority := range l.majority() {
+		if majority == "" {
+			continue
+		}
+		for _, v := range l.Votes {
+			if value, ok := v.Values[property]; ok && normalizeValue(value) != normalizeValue(majority) {
+				result = append(result, &Dissent{
+					Filename: v.Filename,
+					Property: property,
+					Value:    value,
+					Majority: majority,
+				})
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Filename != result[j].Filename {
+			return result[i].Filename < result[j].Filename
+		}
+		return result[i].Property < result[j].Property
+	})
+	return result
+}
+
+// VoteShare the share of the files with an opinion on the property which voted for the value (0 to 1)
+func (l *BasicSurveyor) VoteShare(property, value string) float64 {
+	votes, agree := 0, 0
+	for _, v := range l.Votes {
+		if vote, ok := v.Values[property]; ok {
+			votes++
+			if normalizeValue(vote) == normalizeValue(value) {
+				agree++
+			}
+		}
+	}
+	if votes == 0 {
+		return 0
+	}
+	return float64(agree) / float64(votes)
+}
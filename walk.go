@@ -0,0 +1,36 @@
+package ecg
+
+import (
+	"errors"
+	"io/fs"
+)
+
+// SkipFile returned by a WalkFilter to leave out a single file, for a directory it is the same as fs.SkipDir
+var SkipFile = errors.New("skip this file")
+
+// WalkFilter decides which entries are walked before anything is read from them. It is called with the slash separated
+// path of every file and directory but the root, returning fs.SkipDir for a directory means nothing below it is ever
+// read, SkipFile leaves out a single file and fs.SkipAll stops the walk. Any other error aborts the walk.
+type WalkFilter func(path string, d fs.DirEntry) error
+
+// skip applies the WalkFilter to an entry, true if the entry is to be left out along with the error to hand back to
+// fs.WalkDir
+func (o *analyzeOptions) skip(path string, d fs.DirEntry) (bool, error) {
+	if o.walkFilter == nil || path == "." {
+		return false, nil
+	}
+	err := o.walkFilter(path, d)
+	switch {
+	case err == nil:
+		return false, nil
+	case errors.Is(err, SkipFile):
+		if d.IsDir() {
+			return true, fs.SkipDir
+		}
+		return true, nil
+	case errors.Is(err, fs.SkipDir) && !d.IsDir():
+		// fs.WalkDir would skip the rest of the directory the file is in
+		return true, nil
+	}
+	return true, err
+}
@@ -0,0 +1,63 @@
+package ecg_test
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	ecg "editorconfig-guesser"
+	_ "editorconfig-guesser/fileformats"
+)
+
+func TestWithWalkFilter(t *testing.T) {
+	dir := &countingFS{
+		FS: fstest.MapFS{
+			"main.go":                      &fstest.MapFile{Data: []byte("package main\n\nfunc main() {\n\tprintln()\n}\n")},
+			"skip.go":                      &fstest.MapFile{Data: []byte("package main\n")},
+			"node_modules/a/index.js":      &fstest.MapFile{Data: []byte("function a() {\n  return;\n}\n")},
+			"node_modules/a/.editorconfig": &fstest.MapFile{Data: []byte("[*]\nindent_style = space\n")},
+		},
+		opens: map[string]int{},
+	}
+	var seen []string
+	filter := ecg.WithWalkFilter(func(path string, d fs.DirEntry) error {
+		seen = append(seen, path)
+		switch path {
+		case "node_modules":
+			return fs.SkipDir
+		case "skip.go":
+			return ecg.SkipFile
+		}
+		return nil
+	})
+	guess, err := ecg.Analyze(dir, func(f *ecg.File) bool {
+		if f.Filename == "skip.go" {
+			t.Errorf("ignore was called for %s", f.Filename)
+		}
+		return false
+	}, filter)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if guess.Section("*.js") != nil {
+		t.Errorf("Analyze() surveyed node_modules:\n%s", guess)
+	}
+	configs, err := ecg.LoadEditorConfigs(dir, filter)
+	if err != nil {
+		t.Fatalf("LoadEditorConfigs failed: %v", err)
+	}
+	if len(configs) != 0 {
+		t.Errorf("LoadEditorConfigs() = %v, want none", configs)
+	}
+	for name := range dir.opens {
+		if strings.HasPrefix(name, "node_modules/") || name == "skip.go" {
+			t.Errorf("%s was opened", name)
+		}
+	}
+	for _, p := range seen {
+		if strings.HasPrefix(p, "node_modules/") {
+			t.Errorf("filter was called for %s", p)
+		}
+	}
+}